@@ -6,11 +6,15 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/ayanrajpoot10/hianime-api/config"
 	"github.com/ayanrajpoot10/hianime-api/internal/api"
+	"github.com/ayanrajpoot10/hianime-api/internal/download"
+	"github.com/ayanrajpoot10/hianime-api/internal/ical"
 	"github.com/ayanrajpoot10/hianime-api/internal/scraper"
 )
 
@@ -67,6 +71,42 @@ func main() {
 		}
 		animeID := args[0]
 		app.getAnimeDetails(animeID)
+	case "characters":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime characters <anime-id>")
+			fmt.Println("Example: hianime characters \"one-piece-100\"")
+			return
+		}
+		animeID := args[0]
+		app.getCharacters(animeID)
+	case "franchise":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime franchise <anime-id> [depth]")
+			fmt.Println("Example: hianime franchise \"attack-on-titan-112\" 2")
+			return
+		}
+		animeID := args[0]
+		depth := 1
+		if len(args) >= 2 {
+			if d, err := strconv.Atoi(args[1]); err == nil {
+				depth = d
+			}
+		}
+		app.getFranchise(animeID, depth)
+	case "character":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime character <character-id>")
+			fmt.Println("Example: hianime character \"light-yagami-62\"")
+			return
+		}
+		app.getCharacterDetails(args[0])
+	case "people", "person":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime people <person-id>")
+			fmt.Println("Example: hianime people \"mamoru-miyano-83\"")
+			return
+		}
+		app.getPersonDetails(args[0])
 	case "qtip":
 		if len(args) < 1 {
 			fmt.Println("Usage: hianime qtip <anime-id>")
@@ -75,14 +115,37 @@ func main() {
 		}
 		animeID := args[0]
 		app.getAnimeQtipInfo(animeID)
+	case "resolve":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime resolve <link|anime-id>")
+			fmt.Println("Example: hianime resolve \"https://hianime.to/watch/one-piece-100?ep=2142\"")
+			return
+		}
+		app.resolve(args[0])
 	case "episodes":
 		if len(args) < 1 {
-			fmt.Println("Usage: hianime episodes <anime-id>")
-			fmt.Println("Example: hianime episodes \"one-piece-100\"")
+			fmt.Println("Usage: hianime episodes <anime-id> [number] [--from 1000] [--to 1050] [--fillers exclude|only] [--offset 0] [--limit 50] [--availability]")
+			fmt.Println("Example: hianime episodes \"one-piece-100\" --from 1000 --to 1050")
 			return
 		}
 		animeID := args[0]
+		if len(args) >= 2 {
+			number, err := strconv.Atoi(args[1])
+			if err != nil {
+				log.Fatalf("Invalid episode number: %s", args[1])
+			}
+			app.getEpisode(animeID, number)
+			return
+		}
 		app.getEpisodes(animeID)
+	case "arcs":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime arcs <anime-id> [--arcs-file arcs.json]")
+			fmt.Println("Example: hianime arcs \"one-piece-100\"")
+			return
+		}
+		animeID := args[0]
+		app.getEpisodeArcs(animeID)
 	case "list":
 		if len(args) < 1 {
 			fmt.Println("Usage: hianime list <category> [page]")
@@ -111,6 +174,12 @@ func main() {
 			}
 		}
 		app.getGenreList(genre, page)
+	case "genres":
+		app.getGenres()
+	case "categories":
+		app.getCategories()
+	case "producers":
+		app.getProducers()
 	case "azlist", "az-list":
 		if len(args) < 1 {
 			fmt.Println("Usage: hianime azlist <sort-option> [page]")
@@ -138,12 +207,45 @@ func main() {
 		if len(args) < 3 {
 			fmt.Println("Usage: hianime stream <episode-id> <server-type> <server-name>")
 			fmt.Println("Example: hianime stream \"one-piece-100::ep=2142\" sub HD-1")
+			fmt.Println("Use \"auto\" as server name to try every available server")
 			return
 		}
 		episodeID := args[0]
 		serverType := args[1]
 		serverName := args[2]
 		app.getStreamLinks(episodeID, serverType, serverName)
+	case "watch":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime watch <episode-id>")
+			fmt.Println("Example: hianime watch \"one-piece-100::ep=2142\"")
+			return
+		}
+		episodeID := args[0]
+		app.getWatchInfo(episodeID)
+	case "skip-times", "skip":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime skip-times <episode-id>")
+			fmt.Println("Example: hianime skip-times \"one-piece-100::ep=2142\"")
+			return
+		}
+		episodeID := args[0]
+		app.getSkipTimes(episodeID)
+	case "download":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime download <episode-id> [--type sub] [--server HD-1] [--quality 1080p] [-o file.ts]")
+			fmt.Println("Example: hianime download \"one-piece-100::ep=2142\" --quality 1080p -o one-piece-1.ts")
+			return
+		}
+		episodeID := args[0]
+		app.downloadEpisode(episodeID)
+	case "download-season":
+		if len(args) < 1 {
+			fmt.Println("Usage: hianime download-season <anime-id> [--episodes 1-12,15] [--skip-fillers] [--dir ./downloads]")
+			fmt.Println("Example: hianime download-season \"death-note-60\" --episodes 1-12 --dir ./death-note")
+			return
+		}
+		animeID := args[0]
+		app.downloadSeason(animeID)
 	case "suggestions", "suggest":
 		if len(args) < 1 {
 			fmt.Println("Usage: hianime suggestions <keyword>")
@@ -154,18 +256,34 @@ func main() {
 		app.getSuggestions(keyword)
 	case "schedule":
 		if len(args) < 1 {
-			fmt.Println("Usage: hianime schedule <date> [timezone-offset]")
-			fmt.Println("Example: hianime schedule \"2024-01-15\" -330")
+			fmt.Println("Usage: hianime schedule <date> [timezone]")
+			fmt.Println("Example: hianime schedule \"2024-01-15\" Europe/Berlin")
 			return
 		}
 		date := args[0]
-		tzOffset := -330 // Default to IST
+		timezone := "-330" // Default to IST
 		if len(args) >= 2 {
-			if tz, err := strconv.Atoi(args[1]); err == nil {
-				tzOffset = tz
+			timezone = args[1]
+		}
+		app.getEstimatedSchedule(date, timezone)
+	case "schedule-week", "week":
+		// Both arguments are optional, a date is told apart from a timezone by its format
+		date, timezone := "", "-330" // Default to today in IST
+		for _, arg := range args {
+			if _, err := time.Parse("2006-01-02", arg); err == nil {
+				date = arg
+			} else {
+				timezone = arg
 			}
 		}
-		app.getEstimatedSchedule(date, tzOffset)
+		app.getWeeklySchedule(date, timezone)
+	case "calendar":
+		// Without an anime ID the whole week's schedule is exported
+		animeID := ""
+		if len(args) >= 1 {
+			animeID = args[0]
+		}
+		app.getCalendar(animeID)
 	case "next-episode", "next":
 		if len(args) < 1 {
 			fmt.Println("Usage: hianime next-episode <anime-id>")
@@ -208,10 +326,33 @@ func setupApp() (*App, string, []string) {
 
 	cfg := config.New()
 
-	pflag.StringVar(&cfg.OutputFile, "output", cfg.OutputFile, "Output file path")
+	pflag.StringVarP(&cfg.OutputFile, "output", "o", cfg.OutputFile, "Output file path")
 	pflag.BoolVar(&cfg.Verbose, "verbose", cfg.Verbose, "Enable verbose logging")
+	pflag.BoolVar(&cfg.EnableDebug, "debug", cfg.EnableDebug, "Enable debug logging and endpoints")
+	pflag.StringSliceVar(&cfg.TokenStrategies, "token-strategies", cfg.TokenStrategies, "Token extraction strategy order")
 	pflag.StringVar(&cfg.Port, "port", cfg.Port, "Port to run the server on")
 	pflag.StringVar(&cfg.Host, "host", cfg.Host, "Host to bind the server to")
+	pflag.StringSliceVar(&cfg.ServerPreference, "servers", cfg.ServerPreference, "Server preference order for automatic server selection")
+	pflag.BoolVar(&cfg.DubFallback, "dub-fallback", cfg.DubFallback, "Fall back to sub servers when no dub server works")
+	pflag.BoolVar(&cfg.VerifyStreams, "verify", cfg.VerifyStreams, "Verify stream playlists and segments before returning them")
+	pflag.IntVar(&cfg.EpisodeOffset, "offset", cfg.EpisodeOffset, "Skip this many matching episodes")
+	pflag.IntVar(&cfg.EpisodeLimit, "limit", cfg.EpisodeLimit, "Return at most this many episodes")
+	pflag.IntVar(&cfg.EpisodeFrom, "from", cfg.EpisodeFrom, "First episode number to list")
+	pflag.IntVar(&cfg.EpisodeTo, "to", cfg.EpisodeTo, "Last episode number to list")
+	pflag.StringVar(&cfg.Fillers, "fillers", cfg.Fillers, "Filler filter for episode lists (include, exclude or only)")
+	pflag.BoolVar(&cfg.EpisodeAvailability, "availability", cfg.EpisodeAvailability, "Annotate episode lists with sub/dub server availability")
+	pflag.IntVar(&cfg.AvailabilityWorkers, "availability-workers", cfg.AvailabilityWorkers, "Parallel server lookups for --availability")
+	pflag.StringVar(&cfg.ArcsFile, "arcs-file", cfg.ArcsFile, "JSON file with arc data overriding the bundled dataset")
+	pflag.StringVar(&cfg.StreamType, "type", cfg.StreamType, "Stream type for downloads (sub or dub)")
+	pflag.StringVar(&cfg.StreamServer, "server", cfg.StreamServer, "Server name for downloads")
+	pflag.StringVar(&cfg.Quality, "quality", cfg.Quality, "Download quality (e.g. 1080p, 720p, best, worst)")
+	pflag.IntVar(&cfg.DownloadConcurrency, "concurrency", cfg.DownloadConcurrency, "Number of segments downloaded in parallel")
+	pflag.StringVar(&cfg.DownloadDir, "dir", cfg.DownloadDir, "Directory for season downloads")
+	pflag.StringVar(&cfg.EpisodeRange, "episodes", cfg.EpisodeRange, "Episodes to download (e.g. 1-12,15)")
+	pflag.BoolVar(&cfg.SkipFillers, "skip-fillers", cfg.SkipFillers, "Skip filler episodes")
+	pflag.BoolVar(&cfg.Subtitles, "subtitles", cfg.Subtitles, "Download subtitles alongside video")
+	pflag.StringVar(&cfg.FilenameTemplate, "template", cfg.FilenameTemplate, "File name template for season downloads")
+	pflag.StringVar(&cfg.StateFile, "state", cfg.StateFile, "Job state file for season downloads")
 
 	pflag.CommandLine.Parse(os.Args[2:])
 
@@ -228,7 +369,7 @@ func setupApp() (*App, string, []string) {
 }
 
 func (a *App) startAPIServer() {
-	handler := api.NewHandler(a.scraper)
+	handler := api.NewHandler(a.scraper, a.config)
 	router := api.NewRouter(handler, a.config)
 
 	if err := router.Start(); err != nil {
@@ -288,12 +429,32 @@ func (a *App) getAnimeQtipInfo(animeID string) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) resolve(input string) {
+	if a.config.Verbose {
+		fmt.Printf("Resolving: %s...\n", input)
+	}
+
+	data, err := a.scraper.Resolve(input)
+	if err != nil {
+		log.Fatalf("Failed to resolve: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
 func (a *App) getEpisodes(animeID string) {
 	if a.config.Verbose {
 		fmt.Printf("Getting episodes for anime: %s...\n", animeID)
 	}
 
-	data, err := a.scraper.Episodes(animeID)
+	data, err := a.scraper.FilteredEpisodes(animeID, scraper.EpisodeFilter{
+		Offset:       a.config.EpisodeOffset,
+		Limit:        a.config.EpisodeLimit,
+		From:         a.config.EpisodeFrom,
+		To:           a.config.EpisodeTo,
+		Fillers:      a.config.Fillers,
+		Availability: a.config.EpisodeAvailability,
+	})
 	if err != nil {
 		log.Fatalf("Failed to get episodes: %v", err)
 	}
@@ -301,6 +462,32 @@ func (a *App) getEpisodes(animeID string) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) getEpisode(animeID string, number int) {
+	if a.config.Verbose {
+		fmt.Printf("Getting episode %d of anime: %s...\n", number, animeID)
+	}
+
+	data, err := a.scraper.Episode(animeID, number)
+	if err != nil {
+		log.Fatalf("Failed to get episode: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getEpisodeArcs(animeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting episode arcs for anime: %s...\n", animeID)
+	}
+
+	data, err := a.scraper.EpisodeArcs(animeID)
+	if err != nil {
+		log.Fatalf("Failed to get episode arcs: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
 func (a *App) getAnimeList(category string, page int) {
 	if a.config.Verbose {
 		fmt.Printf("Getting anime list for category '%s' (page %d)...\n", category, page)
@@ -314,6 +501,27 @@ func (a *App) getAnimeList(category string, page int) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) getGenres() {
+	if a.config.Verbose {
+		fmt.Println("Getting genre catalogue...")
+	}
+
+	data, err := a.scraper.Genres()
+	if err != nil {
+		log.Fatalf("Failed to get genres: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getCategories() {
+	outputJSON(a.config, a.scraper.Categories())
+}
+
+func (a *App) getProducers() {
+	outputJSON(a.config, a.scraper.Producers())
+}
+
 func (a *App) getGenreList(genre string, page int) {
 	if a.config.Verbose {
 		fmt.Printf("Getting anime list for genre '%s' (page %d)...\n", genre, page)
@@ -353,6 +561,84 @@ func (a *App) getProducerAnimes(producerName string, page int) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) getWatchInfo(episodeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting watch page info for episode: %s...\n", episodeID)
+	}
+
+	data, err := a.scraper.Watch(episodeID)
+	if err != nil {
+		log.Fatalf("Failed to get watch page info: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getSkipTimes(episodeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting skip times for episode: %s...\n", episodeID)
+	}
+
+	data, err := a.scraper.SkipTimes(episodeID)
+	if err != nil {
+		log.Fatalf("Failed to get skip times: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getCharacters(animeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting characters for: %s...\n", animeID)
+	}
+
+	data, err := a.scraper.Characters(animeID)
+	if err != nil {
+		log.Fatalf("Failed to get characters: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getFranchise(animeID string, depth int) {
+	if a.config.Verbose {
+		fmt.Printf("Building franchise graph for: %s (depth %d)...\n", animeID, depth)
+	}
+
+	data, err := a.scraper.Franchise(animeID, depth)
+	if err != nil {
+		log.Fatalf("Failed to build franchise graph: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getCharacterDetails(characterID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting character details for: %s...\n", characterID)
+	}
+
+	data, err := a.scraper.CharacterDetails(characterID)
+	if err != nil {
+		log.Fatalf("Failed to get character details: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getPersonDetails(personID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting person details for: %s...\n", personID)
+	}
+
+	data, err := a.scraper.PersonDetails(personID)
+	if err != nil {
+		log.Fatalf("Failed to get person details: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
 func (a *App) getServers(episodeID string) {
 	if a.config.Verbose {
 		fmt.Printf("Getting servers for episode: %s...\n", episodeID)
@@ -371,7 +657,7 @@ func (a *App) getStreamLinks(episodeID, serverType, serverName string) {
 		fmt.Printf("Getting stream links for episode: %s (type: %s, server: %s)...\n", episodeID, serverType, serverName)
 	}
 
-	data, err := a.scraper.StreamLinks(episodeID, serverType, serverName)
+	data, err := a.scraper.StreamLinks(episodeID, serverType, serverName, a.config.VerifyStreams)
 	if err != nil {
 		log.Fatalf("Failed to get stream links: %v", err)
 	}
@@ -379,6 +665,67 @@ func (a *App) getStreamLinks(episodeID, serverType, serverName string) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) downloadEpisode(episodeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting stream links for episode: %s (type: %s, server: %s)...\n", episodeID, a.config.StreamType, a.config.StreamServer)
+	}
+
+	stream, err := a.scraper.StreamLinks(episodeID, a.config.StreamType, a.config.StreamServer, a.config.VerifyStreams)
+	if err != nil {
+		log.Fatalf("Failed to get stream links: %v", err)
+	}
+
+	output := a.config.OutputFile
+	if output == "" {
+		output = strings.NewReplacer("::ep=", "-", "/", "-").Replace(episodeID) + ".ts"
+	}
+
+	downloader := download.New(a.config, download.Options{
+		Quality:     a.config.Quality,
+		Concurrency: a.config.DownloadConcurrency,
+		Retries:     a.config.MaxRetries,
+		Headers:     stream.Headers,
+		Progress:    os.Stderr,
+	})
+
+	if err := downloader.Download(stream.Link.File, output); err != nil {
+		log.Fatalf("Failed to download episode: %v", err)
+	}
+
+	fmt.Printf("Episode saved to %s\n", output)
+}
+
+func (a *App) downloadSeason(animeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Downloading episodes of anime: %s (episodes: %s)...\n", animeID, a.config.EpisodeRange)
+	}
+
+	downloader := download.NewSeasonDownloader(a.config, a.scraper, download.SeasonOptions{
+		Episodes:    a.config.EpisodeRange,
+		SkipFillers: a.config.SkipFillers,
+		Subtitles:   a.config.Subtitles,
+		Template:    a.config.FilenameTemplate,
+		Dir:         a.config.DownloadDir,
+		StateFile:   a.config.StateFile,
+		Type:        a.config.StreamType,
+		Server:      a.config.StreamServer,
+		Quality:     a.config.Quality,
+		Concurrency: a.config.DownloadConcurrency,
+		Retries:     a.config.MaxRetries,
+		Progress:    os.Stderr,
+	})
+
+	state, err := downloader.Download(animeID)
+	if state != nil && a.config.Verbose {
+		outputJSON(a.config, state)
+	}
+	if err != nil {
+		log.Fatalf("Failed to download season: %v", err)
+	}
+
+	fmt.Printf("Season saved to %s\n", a.config.DownloadDir)
+}
+
 func (a *App) getSuggestions(keyword string) {
 	if a.config.Verbose {
 		fmt.Printf("Getting suggestions for '%s'...\n", keyword)
@@ -392,12 +739,17 @@ func (a *App) getSuggestions(keyword string) {
 	outputJSON(a.config, data)
 }
 
-func (a *App) getEstimatedSchedule(date string, tzOffset int) {
+func (a *App) getEstimatedSchedule(date, timezone string) {
 	if a.config.Verbose {
-		fmt.Printf("Getting estimated schedule for date '%s' (timezone offset: %d)...\n", date, tzOffset)
+		fmt.Printf("Getting estimated schedule for date '%s' (timezone: %s)...\n", date, timezone)
+	}
+
+	loc, err := scraper.ParseTimezone(timezone)
+	if err != nil {
+		log.Fatalf("Failed to get estimated schedule: %v", err)
 	}
 
-	data, err := a.scraper.GetEstimatedSchedule(date, tzOffset)
+	data, err := a.scraper.GetScheduleIn(date, loc)
 	if err != nil {
 		log.Fatalf("Failed to get estimated schedule: %v", err)
 	}
@@ -405,6 +757,52 @@ func (a *App) getEstimatedSchedule(date string, tzOffset int) {
 	outputJSON(a.config, data)
 }
 
+func (a *App) getWeeklySchedule(date, timezone string) {
+	if a.config.Verbose {
+		fmt.Printf("Getting weekly schedule from '%s' (timezone: %s)...\n", date, timezone)
+	}
+
+	loc, err := scraper.ParseTimezone(timezone)
+	if err != nil {
+		log.Fatalf("Failed to get weekly schedule: %v", err)
+	}
+
+	data, err := a.scraper.GetWeeklySchedule(date, loc)
+	if err != nil {
+		log.Fatalf("Failed to get weekly schedule: %v", err)
+	}
+
+	outputJSON(a.config, data)
+}
+
+func (a *App) getCalendar(animeID string) {
+	if a.config.Verbose {
+		fmt.Printf("Building calendar for '%s'...\n", animeID)
+	}
+
+	var calendar *ical.Calendar
+	var err error
+	if animeID == "" {
+		calendar, err = a.scraper.ScheduleCalendar(nil, nil)
+	} else {
+		calendar, err = a.scraper.AnimeCalendar(animeID)
+	}
+	if err != nil {
+		log.Fatalf("Failed to build calendar: %v", err)
+	}
+
+	if a.config.OutputFile == "" {
+		fmt.Print(calendar.Render())
+		return
+	}
+	if err := os.WriteFile(a.config.OutputFile, []byte(calendar.Render()), 0644); err != nil {
+		log.Fatalf("Failed to write to file: %v", err)
+	}
+	if a.config.Verbose {
+		fmt.Printf("Output written to %s\n", a.config.OutputFile)
+	}
+}
+
 func (a *App) getNextEpisodeSchedule(animeID string) {
 	if a.config.Verbose {
 		fmt.Printf("Getting next episode schedule for anime: %s...\n", animeID)
@@ -430,24 +828,60 @@ COMMANDS:
     search <keyword> [page]        Search for anime
     anime <anime-id>               Get anime details
     qtip <anime-id>                Get anime qtip information
-    episodes <anime-id>            Get episode list
+    resolve <link|anime-id>        Resolve a link, numeric or slug ID to canonical IDs
+    characters <anime-id>          Get characters and voice actors
+    franchise <anime-id> [depth]   Get the seasons and related anime graph
+    character <character-id>       Get character bio, voice actors and animeography
+    people <person-id>             Get voice actor bio and roles
+    episodes <anime-id> [number]   Get episode list, or a single episode by number
+    arcs <anime-id>                Get episodes grouped by arc with canon/filler types
     list <category> [page]         Get anime list by category
     genre <genre-name> [page]      Get anime list by genre
+    genres                         List genres with slugs and links
+    categories                     List categories accepted by list
+    producers                      List known producers and studios
     azlist <sort-option> [page]    Get anime list sorted alphabetically (A-Z)
     servers <episode-id>           Get available servers for episode
+    watch <episode-id>             Get servers of every type, notices and next-airing banner
     stream <episode-id> <type> <server>  Get streaming links for episode
+    skip-times <episode-id>        Get intro/outro/recap/credits skip times across servers
+    download <episode-id>          Download an episode into a single .ts file
+    download-season <anime-id>     Download a range of episodes with resumable state
     suggestions <keyword>          Get search suggestions
     schedule <date> [timezone]     Get estimated schedule for date (YYYY-MM-DD)
+    schedule-week [date] [timezone]  Get seven days of schedule grouped by day
+    calendar [anime-id]            Export airings as an iCalendar (.ics) feed
     next-episode <anime-id>        Get next episode schedule for anime
     producer <producer-name> [page] Get anime list from producer/studio
     help                           Show this help message
     version                        Show version information
 
 OPTIONS:
-    --output <file>               Output to file
+    -o, --output <file>           Output to file
     --verbose                     Enable verbose logging
+    --debug                       Log token extraction and enable /api/debug endpoints
+    --token-strategies <list>     Token strategy order (default: meta,dataDpi,nonce,windowString,windowObject,comment)
     --port <port>                 Server port (default: 3030)
     --host <host>                 Server host (default: 0.0.0.0)
+    --servers <list>              Server preference for "auto" (default: HD-1,HD-2,HD-3)
+    --dub-fallback                Fall back from dub to sub for "auto" (default: true)
+    --verify                      Verify stream links before returning them
+    --from <n>, --to <n>          Episode number range for episode lists
+    --fillers <exclude|only>      Filler filter for episode lists
+    --offset <n>, --limit <n>     Page through episode lists
+    --availability                Add sub/dub servers to each listed episode (needs --limit <= 100)
+    --availability-workers <n>    Parallel server lookups for --availability (default: 8)
+    --arcs-file <file>            Arc data overriding the bundled dataset
+    --type <sub|dub>              Stream type for downloads (default: sub)
+    --server <name>               Server name for downloads (default: HD-1)
+    --quality <quality>           Download quality, e.g. 1080p (default: best)
+    --concurrency <n>             Parallel segment downloads (default: 4)
+    --dir <path>                  Directory for season downloads (default: .)
+    --episodes <ranges>           Episodes for season downloads, e.g. 1-12,15
+    --skip-fillers                Skip filler episodes in season downloads
+    --subtitles                   Download subtitles alongside video (default: true)
+    --template <template>         File name template (default: "{title} - S01E{ep:02} [{type}].ts")
+    --state <file>                Job state file (default: <dir>/<anime-id>.state.json)
 
 EXAMPLES:
     hianime serve
@@ -455,7 +889,12 @@ EXAMPLES:
     hianime search "death note" 1
     hianime anime "death-note-60"
     hianime schedule "2025-09-15" -330
-    hianime list most-popular 1`)
+    hianime schedule-week Europe/Berlin
+    hianime calendar "one-piece-100" -o one-piece.ics
+    hianime list most-popular 1
+    hianime stream "one-piece-100::ep=2142" dub auto
+    hianime download "one-piece-100::ep=2142" --quality 1080p -o one-piece-1.ts
+    hianime download-season "death-note-60" --episodes 1-12,15 --dir ./death-note`)
 }
 
 func printVersion() {
@@ -1,11 +1,32 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// FallbackHost describes a mirror tried when the main megacloud flow fails.
+// Paths and referers are templates: {host}, {episode}, {type} and {id} are
+// replaced with the host, episode number, stream type and mirror source ID.
+// Servers limits the host to the listed server names and ExcludeServers skips
+// it for the listed ones.
+type FallbackHost struct {
+	Host           string   `json:"host"`
+	StreamPath     string   `json:"stream_path"`
+	SourcesPath    string   `json:"sources_path"`
+	Referer        string   `json:"referer"`
+	StreamReferer  string   `json:"stream_referer"`
+	Servers        []string `json:"servers"`
+	ExcludeServers []string `json:"exclude_servers"`
+}
+
+// fallbackTemplateHost is the default mirror whose paths and referers fill in
+// hosts configured without them
+const fallbackTemplateHost = "megaplay.buzz"
+
 // Config holds all configuration for the application
 type Config struct {
 	// Server configuration
@@ -21,35 +42,153 @@ type Config struct {
 	MaxRetries int           `json:"max_retries"`
 
 	// CLI configuration
-	OutputFile   string `json:"output_file"`
-	Verbose      bool   `json:"verbose"`
+	OutputFile string `json:"output_file"`
+	Verbose    bool   `json:"verbose"`
+
+	// Streaming configuration
+	ServerPreference []string       `json:"server_preference"`
+	DubFallback      bool           `json:"dub_fallback"`
+	VerifyStreams    bool           `json:"verify_streams"`
+	TokenStrategies  []string       `json:"token_strategies"`
+	FallbackHosts    []FallbackHost `json:"fallback_hosts"`
+
+	// Episode listing configuration
+	EpisodeOffset int    `json:"episode_offset"`
+	EpisodeLimit  int    `json:"episode_limit"`
+	EpisodeFrom   int    `json:"episode_from"`
+	EpisodeTo     int    `json:"episode_to"`
+	Fillers       string `json:"fillers"`
+	ArcsFile      string `json:"arcs_file"`
+
+	// Episode availability configuration
+	EpisodeAvailability bool          `json:"episode_availability"`
+	AvailabilityWorkers int           `json:"availability_workers"`
+	AvailabilityTTL     time.Duration `json:"availability_ttl"`
+
+	// Download configuration
+	StreamType          string `json:"stream_type"`
+	StreamServer        string `json:"stream_server"`
+	Quality             string `json:"quality"`
+	DownloadConcurrency int    `json:"download_concurrency"`
+	DownloadDir         string `json:"download_dir"`
+	EpisodeRange        string `json:"episode_range"`
+	SkipFillers         bool   `json:"skip_fillers"`
+	Subtitles           bool   `json:"subtitles"`
+	FilenameTemplate    string `json:"filename_template"`
+	StateFile           string `json:"state_file"`
 
 	// API configuration
 	EnableCORS     bool     `json:"enable_cors"`
 	AllowedOrigins []string `json:"allowed_origins"`
+	EnableDebug    bool     `json:"enable_debug"`
+	ProxyHosts     []string `json:"proxy_hosts"`
 
 	// Cache configuration
-	EnableCache bool          `json:"enable_cache"`
-	CacheTTL    time.Duration `json:"cache_ttl"`
+	EnableCache  bool          `json:"enable_cache"`
+	CacheTTL     time.Duration `json:"cache_ttl"`
+	SkipTimesTTL time.Duration `json:"skip_times_ttl"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Port:           "3030",
-		Host:           "0.0.0.0",
-		ReadTimeout:    30 * time.Second,
-		WriteTimeout:   30 * time.Second,
-		BaseURL:        "https://hianime.to",
-		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
-		Timeout:        30 * time.Second,
-		MaxRetries:     3,
-		Verbose:        false,
-		EnableCORS:     true,
-		AllowedOrigins: []string{"*"},
-		EnableCache:    true,
-		CacheTTL:       5 * time.Minute,
+		Port:                "3030",
+		Host:                "0.0.0.0",
+		ReadTimeout:         30 * time.Second,
+		WriteTimeout:        30 * time.Second,
+		BaseURL:             "https://hianime.to",
+		UserAgent:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+		Timeout:             30 * time.Second,
+		MaxRetries:          3,
+		Verbose:             false,
+		ServerPreference:    []string{"HD-1", "HD-2", "HD-3"},
+		DubFallback:         true,
+		FallbackHosts:       DefaultFallbackHosts(),
+		StreamType:          "sub",
+		StreamServer:        "HD-1",
+		Quality:             "best",
+		DownloadConcurrency: 4,
+		DownloadDir:         ".",
+		Subtitles:           true,
+		FilenameTemplate:    "{title} - S01E{ep:02} [{type}].ts",
+		EnableCORS:          true,
+		AllowedOrigins:      []string{"*"},
+		EnableCache:         true,
+		CacheTTL:            5 * time.Minute,
+		SkipTimesTTL:        24 * time.Hour,
+		AvailabilityWorkers: 8,
+		AvailabilityTTL:     6 * time.Hour,
+	}
+}
+
+// DefaultFallbackHosts returns the built-in megacloud mirror chain
+func DefaultFallbackHosts() []FallbackHost {
+	return []FallbackHost{
+		{
+			Host:          "megaplay.buzz",
+			StreamPath:    "/stream/s-2/{episode}/{type}",
+			SourcesPath:   "/stream/getSources?id={id}",
+			Referer:       "https://megaplay.buzz/",
+			StreamReferer: "https://{host}/",
+			Servers:       []string{"HD-1"},
+		},
+		{
+			Host:           "vidwish.live",
+			StreamPath:     "/stream/s-2/{episode}/{type}",
+			SourcesPath:    "/stream/getSources?id={id}",
+			Referer:        "https://megaplay.buzz/",
+			StreamReferer:  "https://{host}/",
+			ExcludeServers: []string{"HD-1"},
+		},
+	}
+}
+
+// DefaultFallbackHost returns the built-in entry for a mirror host
+func DefaultFallbackHost(name string) (FallbackHost, bool) {
+	for _, host := range DefaultFallbackHosts() {
+		if strings.EqualFold(host.Host, name) {
+			return host, true
+		}
 	}
+	return FallbackHost{}, false
+}
+
+// FallbackTemplate returns the paths and referers used for hosts configured
+// without them
+func FallbackTemplate() FallbackHost {
+	template, _ := DefaultFallbackHost(fallbackTemplateHost)
+	template.Host = ""
+	template.Servers = nil
+	template.ExcludeServers = nil
+	return template
+}
+
+// parseFallbackHosts reads a JSON array of fallback hosts, or a comma-separated
+// list of host names. Built-in hosts keep their default entry, other names use
+// the default paths and referers for every server.
+func parseFallbackHosts(value string) ([]FallbackHost, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var hosts []FallbackHost
+		if err := json.Unmarshal([]byte(value), &hosts); err != nil {
+			return nil, err
+		}
+		return hosts, nil
+	}
+
+	var hosts []FallbackHost
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		host, ok := DefaultFallbackHost(name)
+		if !ok {
+			host = FallbackTemplate()
+			host.Host = name
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
 }
 
 // LoadFromEnv loads configuration from environment variables
@@ -82,6 +221,70 @@ func (c *Config) LoadFromEnv() {
 		}
 	}
 
+	if preferenceStr := os.Getenv("SERVER_PREFERENCE"); preferenceStr != "" {
+		var preference []string
+		for _, name := range strings.Split(preferenceStr, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				preference = append(preference, name)
+			}
+		}
+		c.ServerPreference = preference
+	}
+
+	if dubFallbackStr := os.Getenv("DUB_FALLBACK"); dubFallbackStr != "" {
+		if dubFallback, err := strconv.ParseBool(dubFallbackStr); err == nil {
+			c.DubFallback = dubFallback
+		}
+	}
+
+	if verifyStr := os.Getenv("VERIFY_STREAMS"); verifyStr != "" {
+		if verify, err := strconv.ParseBool(verifyStr); err == nil {
+			c.VerifyStreams = verify
+		}
+	}
+
+	if strategiesStr := os.Getenv("TOKEN_STRATEGIES"); strategiesStr != "" {
+		var strategies []string
+		for _, name := range strings.Split(strategiesStr, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				strategies = append(strategies, name)
+			}
+		}
+		c.TokenStrategies = strategies
+	}
+
+	if fallbackStr := os.Getenv("FALLBACK_HOSTS"); fallbackStr != "" {
+		if hosts, err := parseFallbackHosts(fallbackStr); err == nil {
+			c.FallbackHosts = hosts
+		}
+	}
+
+	if arcsFile := os.Getenv("ARCS_FILE"); arcsFile != "" {
+		c.ArcsFile = arcsFile
+	}
+
+	if workersStr := os.Getenv("AVAILABILITY_WORKERS"); workersStr != "" {
+		if workers, err := strconv.Atoi(workersStr); err == nil && workers > 0 {
+			c.AvailabilityWorkers = workers
+		}
+	}
+
+	if availabilityTTLStr := os.Getenv("AVAILABILITY_TTL"); availabilityTTLStr != "" {
+		if availabilityTTL, err := time.ParseDuration(availabilityTTLStr); err == nil {
+			c.AvailabilityTTL = availabilityTTL
+		}
+	}
+
+	if proxyHostsStr := os.Getenv("PROXY_HOSTS"); proxyHostsStr != "" {
+		var hosts []string
+		for _, host := range strings.Split(proxyHostsStr, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+		c.ProxyHosts = hosts
+	}
+
 	if verboseStr := os.Getenv("VERBOSE"); verboseStr != "" {
 		if verbose, err := strconv.ParseBool(verboseStr); err == nil {
 			c.Verbose = verbose
@@ -94,6 +297,12 @@ func (c *Config) LoadFromEnv() {
 		}
 	}
 
+	if debugStr := os.Getenv("DEBUG"); debugStr != "" {
+		if debug, err := strconv.ParseBool(debugStr); err == nil {
+			c.EnableDebug = debug
+		}
+	}
+
 	if enableCacheStr := os.Getenv("ENABLE_CACHE"); enableCacheStr != "" {
 		if enableCache, err := strconv.ParseBool(enableCacheStr); err == nil {
 			c.EnableCache = enableCache
@@ -105,6 +314,12 @@ func (c *Config) LoadFromEnv() {
 			c.CacheTTL = cacheTTL
 		}
 	}
+
+	if skipTTLStr := os.Getenv("SKIP_TIMES_TTL"); skipTTLStr != "" {
+		if skipTTL, err := time.ParseDuration(skipTTLStr); err == nil {
+			c.SkipTimesTTL = skipTTL
+		}
+	}
 }
 
 // New creates a new configuration instance with defaults, env vars, and flags applied
@@ -6,9 +6,7 @@ require (
 	github.com/PuerkitoBio/goquery v1.10.3
 	github.com/spf13/pflag v1.0.10
 	golang.org/x/crypto v0.42.0
+	golang.org/x/net v0.43.0
 )
 
-require (
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.43.0 // indirect
-)
+require github.com/andybalholm/cascadia v1.3.3 // indirect
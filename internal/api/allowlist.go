@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/internal/cache"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// proxyHostTTL is how long a host learned from a stream stays reachable through the proxy
+const proxyHostTTL = 6 * time.Hour
+
+// errHostNotAllowed is returned for upstream URLs outside the allowlist
+var errHostNotAllowed = errors.New("upstream host not allowed")
+
+// hostAllowlist holds the upstream hosts the proxy endpoints may fetch from: the
+// configured hosts and their subdomains, and hosts learned from decrypted streams
+// and the playlists proxied for them. Anything else is refused, so the proxy can't
+// be pointed at internal services.
+type hostAllowlist struct {
+	configured []string
+	learned    *cache.Cache[bool]
+}
+
+// newHostAllowlist creates an allowlist seeded with the configured hosts
+func newHostAllowlist(hosts []string) *hostAllowlist {
+	configured := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.Trim(strings.TrimSpace(host), ".")); host != "" {
+			configured = append(configured, host)
+		}
+	}
+
+	return &hostAllowlist{
+		configured: configured,
+		learned:    cache.New[bool](proxyHostTTL),
+	}
+}
+
+// Allows reports whether host, without port, may be fetched
+func (a *hostAllowlist) Allows(host string) bool {
+	host = strings.ToLower(host)
+	if host == "" {
+		return false
+	}
+
+	for _, allowed := range a.configured {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+
+	_, ok := a.learned.Get(host)
+	return ok
+}
+
+// Learn allows the host of an absolute http(s) URL
+func (a *hostAllowlist) Learn(rawURL string) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return
+	}
+	a.learned.Set(strings.ToLower(u.Hostname()), true)
+}
+
+// LearnStream allows the hosts of a stream's playlist and tracks
+func (a *hostAllowlist) LearnStream(stream *models.StreamResponse) {
+	if stream == nil {
+		return
+	}
+	a.Learn(stream.Link.File)
+	for _, track := range stream.Tracks {
+		a.Learn(track.File)
+	}
+}
+
+// Parse validates an upstream URL given to a proxy endpoint. It returns the status
+// code to answer with when the URL is malformed or its host isn't allowed.
+func (a *hostAllowlist) Parse(target string) (*url.URL, int, error) {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid upstream url: %s", target)
+	}
+
+	if !a.Allows(u.Hostname()) {
+		return nil, http.StatusForbidden, fmt.Errorf("%w: %s", errHostNotAllowed, u.Hostname())
+	}
+
+	return u, 0, nil
+}
+
+// checkRedirect stops the upstream client from following redirects off the allowlist
+func (a *hostAllowlist) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if !a.Allows(req.URL.Hostname()) {
+		return fmt.Errorf("%w: %s", errHostNotAllowed, req.URL.Hostname())
+	}
+	return nil
+}
@@ -3,10 +3,18 @@ package api
 import (
 	_ "embed"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/internal/ical"
 	"github.com/ayanrajpoot10/hianime-api/internal/scraper"
+	"github.com/ayanrajpoot10/hianime-api/pkg/httpclient"
 	"github.com/ayanrajpoot10/hianime-api/pkg/models"
 )
 
@@ -15,13 +23,22 @@ var htmlTemplate string
 
 // Handler holds the scraper instance and handles HTTP requests
 type Handler struct {
-	scraper *scraper.Scraper
+	scraper  *scraper.Scraper
+	config   *config.Config
+	upstream *httpclient.Client
+
+	proxyHosts *hostAllowlist
 }
 
 // NewHandler creates a new API handler
-func NewHandler(s *scraper.Scraper) *Handler {
+func NewHandler(s *scraper.Scraper, cfg *config.Config) *Handler {
+	proxyHosts := newHostAllowlist(cfg.ProxyHosts)
+
 	return &Handler{
-		scraper: s,
+		scraper:    s,
+		config:     cfg,
+		upstream:   newProxyClient(cfg, proxyHosts),
+		proxyHosts: proxyHosts,
 	}
 }
 
@@ -53,6 +70,19 @@ func writeError(w http.ResponseWriter, statusCode int, err error) {
 	writeJSON(w, statusCode, err.Error())
 }
 
+// errorStatus returns 404 for anime and episodes the site doesn't have, 400 for
+// malformed anime IDs and for categories and genres missing from the catalogue, and
+// 500 for other failures
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, scraper.ErrAnimeNotFound), errors.Is(err, scraper.ErrEpisodeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, scraper.ErrInvalidID), errors.Is(err, scraper.ErrUnsupported):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // Homepage handles GET /api/home
 func (h *Handler) Homepage(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -112,7 +142,34 @@ func (h *Handler) AnimeQtipInfo(w http.ResponseWriter, req *http.Request) {
 
 	data, err := h.scraper.GetAnimeQtipInfo(animeID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err)
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// Resolve handles GET /api/resolve?url={link}
+func (h *Handler) Resolve(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	input := req.URL.Query().Get("url")
+	if input == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	if err := h.scraper.ValidateAnimeRef(input); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := h.scraper.Resolve(input)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
 		return
 	}
 
@@ -133,14 +190,49 @@ func (h *Handler) EstimatedSchedule(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	tzOffset := -330 // Default timezone offset (IST)
-	if tzStr := query.Get("tzOffset"); tzStr != "" {
-		if tz, err := strconv.Atoi(tzStr); err == nil {
-			tzOffset = tz
+	if err := h.scraper.ValidateScheduleDate(date); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	loc, err := scheduleLocation(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := h.scraper.GetScheduleIn(date, loc)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// WeeklySchedule handles GET /api/schedule/week
+func (h *Handler) WeeklySchedule(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	query := req.URL.Query()
+	date := query.Get("date")
+	if date != "" {
+		if err := h.scraper.ValidateScheduleDate(date); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
 		}
 	}
 
-	data, err := h.scraper.GetEstimatedSchedule(date, tzOffset)
+	loc, err := scheduleLocation(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := h.scraper.GetWeeklySchedule(date, loc)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
@@ -149,6 +241,106 @@ func (h *Handler) EstimatedSchedule(w http.ResponseWriter, req *http.Request) {
 	writeJSON(w, http.StatusOK, data)
 }
 
+// ScheduleCalendar handles GET /api/schedule.ics
+func (h *Handler) ScheduleCalendar(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	query := req.URL.Query()
+	animeIDs := listParam(query, "ids")
+	genres := listParam(query, "genres")
+
+	for _, animeID := range animeIDs {
+		if err := h.scraper.ValidateAnimeRef(animeID); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	for _, genre := range genres {
+		if err := h.scraper.ValidateGenre(genre); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	calendar, err := h.scraper.ScheduleCalendar(animeIDs, genres)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeCalendar(w, calendar)
+}
+
+// AnimeCalendar handles GET /api/anime/{id}/calendar.ics
+func (h *Handler) AnimeCalendar(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract anime ID from URL path
+	animeID, found := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/api/anime/"), "/calendar.ics")
+
+	if !found || animeID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	if err := h.scraper.ValidateAnimeRef(animeID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	calendar, err := h.scraper.AnimeCalendar(animeID)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeCalendar(w, calendar)
+}
+
+// writeCalendar writes a calendar as an iCalendar feed
+func writeCalendar(w http.ResponseWriter, calendar *ical.Calendar) {
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(calendar.Render()))
+}
+
+// listParam reads a list from comma-separated or repeated query parameters
+func listParam(query url.Values, key string) []string {
+	var values []string
+	for _, value := range query[key] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// scheduleLocation reads the timezone of a schedule request from tz, an IANA name,
+// falling back to the minute offset in tzOffset and then to IST
+func scheduleLocation(query url.Values) (*time.Location, error) {
+	if tz := query.Get("tz"); tz != "" {
+		return scraper.ParseTimezone(tz)
+	}
+
+	tzOffset := -330 // Default timezone offset (IST)
+	if tzStr := query.Get("tzOffset"); tzStr != "" {
+		if tz, err := strconv.Atoi(tzStr); err == nil {
+			tzOffset = tz
+		}
+	}
+
+	return scraper.ParseTimezone(strconv.Itoa(tzOffset))
+}
+
 // NextEpisodeSchedule handles GET /api/next-episode/{id}
 func (h *Handler) NextEpisodeSchedule(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -174,6 +366,67 @@ func (h *Handler) NextEpisodeSchedule(w http.ResponseWriter, req *http.Request)
 	writeJSON(w, http.StatusOK, data)
 }
 
+// SkipTimes handles GET /api/skip-times/{episode-id}
+func (h *Handler) SkipTimes(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract episode ID from URL path, "?ep=" may be passed as a query parameter
+	path := req.URL.Path
+	episodeID := path[len("/api/skip-times/"):]
+	if ep := req.URL.Query().Get("ep"); ep != "" && !strings.Contains(episodeID, "::ep=") {
+		episodeID += "::ep=" + ep
+	}
+
+	if episodeID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	data, err := h.scraper.SkipTimes(episodeID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// Watch handles GET /api/watch/{episode-id}
+func (h *Handler) Watch(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract episode ID from URL path, "?ep=" may be passed as a query parameter
+	path := req.URL.Path
+	episodeID := path[len("/api/watch/"):]
+	if episodeID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	if ep := req.URL.Query().Get("ep"); ep != "" && !strings.Contains(episodeID, "::ep=") {
+		episodeID += "::ep=" + ep
+	}
+
+	if err := h.scraper.ValidateEpisodeID(episodeID); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := h.scraper.Watch(episodeID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
 // Search handles GET /api/search
 func (h *Handler) Search(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -227,25 +480,89 @@ func (h *Handler) Suggestions(w http.ResponseWriter, req *http.Request) {
 	writeJSON(w, http.StatusOK, data)
 }
 
-// Episodes handles GET /api/episodes/{id}
+// Episodes handles GET /api/episodes/{id}, GET /api/episodes/{id}/{number} and GET /api/episodes/{id}/arcs
 func (h *Handler) Episodes(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
 		return
 	}
 
-	// Extract anime ID from URL path
+	// Extract anime ID and optional episode number from URL path
 	path := req.URL.Path
 	animeID := path[len("/api/episodes/"):]
+	numberStr := ""
+	if i := strings.Index(animeID, "/"); i >= 0 {
+		animeID, numberStr = animeID[:i], animeID[i+1:]
+	}
 
 	if animeID == "" {
 		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
 		return
 	}
 
-	data, err := h.scraper.Episodes(animeID)
+	if numberStr == "arcs" {
+		data, err := h.scraper.EpisodeArcs(animeID)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, data)
+		return
+	}
+
+	if numberStr != "" {
+		number, err := strconv.Atoi(numberStr)
+		if err != nil || number < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid episode number: %s", numberStr))
+			return
+		}
+
+		data, err := h.scraper.Episode(animeID, number)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, data)
+		return
+	}
+
+	query := req.URL.Query()
+	filter := scraper.EpisodeFilter{
+		Fillers: query.Get("fillers"),
+	}
+	if value := query.Get("availability"); value != "" {
+		availability, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid availability: %s", value))
+			return
+		}
+		filter.Availability = availability
+	}
+	for name, target := range map[string]*int{
+		"offset": &filter.Offset,
+		"limit":  &filter.Limit,
+		"from":   &filter.From,
+		"to":     &filter.To,
+	} {
+		if value := query.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %s", name, value))
+				return
+			}
+			*target = n
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	data, err := h.scraper.FilteredEpisodes(animeID, filter)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err)
+		writeError(w, errorStatus(err), err)
 		return
 	}
 
@@ -299,15 +616,98 @@ func (h *Handler) Stream(w http.ResponseWriter, req *http.Request) {
 		serverName = "HD-1"
 	}
 
-	data, err := h.scraper.StreamLinks(episodeID, serverType, serverName)
+	verify := h.config.VerifyStreams
+	if verifyStr := query.Get("verify"); verifyStr != "" {
+		v, err := strconv.ParseBool(verifyStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		verify = v
+	}
+
+	var data *models.StreamResponse
+	var err error
+	if strings.EqualFold(serverName, "auto") {
+		fallback := h.config.DubFallback
+		if fallbackStr := query.Get("fallback"); fallbackStr != "" {
+			f, parseErr := strconv.ParseBool(fallbackStr)
+			if parseErr != nil {
+				writeError(w, http.StatusBadRequest, parseErr)
+				return
+			}
+			fallback = f
+		}
+		data, err = h.scraper.AutoStreamLinks(episodeID, serverType, fallback, verify)
+	} else {
+		data, err = h.scraper.StreamLinks(episodeID, serverType, serverName, verify)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	// The proxy endpoints may now fetch the playlist and tracks of this stream
+	h.proxyHosts.LearnStream(data)
+
 	writeJSON(w, http.StatusOK, data)
 }
 
+// DebugToken handles GET /api/debug/token
+func (h *Handler) DebugToken(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	if !h.config.EnableDebug {
+		writeError(w, http.StatusNotFound, fmt.Errorf("debug endpoints are disabled"))
+		return
+	}
+
+	query := req.URL.Query()
+
+	var data *models.TokenDiagnostics
+	var err error
+	if embedURL := query.Get("url"); embedURL != "" {
+		data, err = h.scraper.EmbedTokenDiagnostics(embedURL)
+	} else {
+		episodeID := query.Get("id")
+		if episodeID == "" {
+			writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+			return
+		}
+
+		serverType := query.Get("type")
+		if serverType == "" {
+			serverType = "sub"
+		}
+
+		serverName := query.Get("server")
+		if serverName == "" {
+			serverName = "HD-1"
+		}
+
+		data, err = h.scraper.TokenDiagnostics(episodeID, serverType, serverName)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// FallbackStats handles GET /api/stats/fallback
+func (h *Handler) FallbackStats(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.scraper.FallbackStats())
+}
+
 // AnimeList handles GET /api/animes/{category}
 func (h *Handler) AnimeList(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -334,7 +734,7 @@ func (h *Handler) AnimeList(w http.ResponseWriter, req *http.Request) {
 
 	data, err := h.scraper.AnimeList(category, page)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err)
+		writeError(w, errorStatus(err), err)
 		return
 	}
 
@@ -366,6 +766,22 @@ func (h *Handler) GenreList(w http.ResponseWriter, req *http.Request) {
 	}
 
 	data, err := h.scraper.GenreList(genre, page)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// Genres handles GET /api/genres
+func (h *Handler) Genres(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	data, err := h.scraper.Genres()
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
@@ -374,6 +790,26 @@ func (h *Handler) GenreList(w http.ResponseWriter, req *http.Request) {
 	writeJSON(w, http.StatusOK, data)
 }
 
+// Categories handles GET /api/categories
+func (h *Handler) Categories(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.scraper.Categories())
+}
+
+// Producers handles GET /api/producers
+func (h *Handler) Producers(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.scraper.Producers())
+}
+
 // AZList handles GET /api/azlist/{sortOption}
 func (h *Handler) AZList(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -407,6 +843,111 @@ func (h *Handler) AZList(w http.ResponseWriter, req *http.Request) {
 	writeJSON(w, http.StatusOK, data)
 }
 
+// Characters handles GET /api/anime/{id}/characters
+func (h *Handler) Characters(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract anime ID from URL path
+	animeID, found := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/api/anime/"), "/characters")
+
+	if !found || animeID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	data, err := h.scraper.Characters(animeID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// Franchise handles GET /api/anime/{id}/franchise
+func (h *Handler) Franchise(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract anime ID from URL path
+	animeID, found := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/api/anime/"), "/franchise")
+
+	if !found || animeID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	depth := 1
+	if depthStr := req.URL.Query().Get("depth"); depthStr != "" {
+		if d, err := strconv.Atoi(depthStr); err == nil && d > 0 {
+			depth = d
+		}
+	}
+
+	data, err := h.scraper.Franchise(animeID, depth)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// CharacterDetails handles GET /api/character/{id}
+func (h *Handler) CharacterDetails(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract character ID from URL path
+	path := req.URL.Path
+	characterID := path[len("/api/character/"):]
+
+	if characterID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	data, err := h.scraper.CharacterDetails(characterID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
+// PersonDetails handles GET /api/people/{id}
+func (h *Handler) PersonDetails(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	// Extract person ID from URL path
+	path := req.URL.Path
+	personID := path[len("/api/people/"):]
+
+	if personID == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	data, err := h.scraper.PersonDetails(personID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, data)
+}
+
 // Producer handles GET /api/producer/{producer-name}
 func (h *Handler) Producer(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
@@ -482,14 +1023,34 @@ func (h *Handler) APIRoot(w http.ResponseWriter, req *http.Request) {
 			"search":                "/api/search?keyword={query}&page={page}",
 			"suggestions":           "/api/suggestion?keyword={query}",
 			"anime":                 "/api/anime/{id}",
+			"characters":            "/api/anime/{id}/characters",
+			"franchise":             "/api/anime/{id}/franchise?depth={1-3}",
+			"character":             "/api/character/{id}",
+			"people":                "/api/people/{id}",
 			"qtip":                  "/api/qtip/{id}",
-			"episodes":              "/api/episodes/{id}",
+			"resolve":               "/api/resolve?url={link|id}",
+			"episodes":              "/api/episodes/{id}?offset={n}&limit={n}&from={ep}&to={ep}&fillers={exclude|only}&availability={bool}",
+			"episode":               "/api/episodes/{id}/{number}",
+			"episode_arcs":          "/api/episodes/{id}/arcs",
 			"anime_list":            "/api/animes/{category}?page={page}",
 			"genre_list":            "/api/genre/{genre}?page={page}",
+			"genres":                "/api/genres",
+			"categories":            "/api/categories",
+			"producers":             "/api/producers",
 			"azlist":                "/api/azlist/{sortOption}?page={page}",
 			"servers":               "/api/servers?id={episodeId}",
-			"stream":                "/api/stream?id={episodeId}&type={sub|dub}&server={serverName}",
-			"estimated_schedule":    "/api/schedule?date={YYYY-MM-DD}&tzOffset={offset}",
+			"watch":                 "/api/watch/{episodeId}",
+			"stream":                "/api/stream?id={episodeId}&type={sub|dub|raw}&server={serverName|auto}&fallback={true|false}&verify={true|false}",
+			"skip_times":            "/api/skip-times/{episodeId}",
+			"thumbnails":            "/api/stream/thumbnails?id={episodeId}&type={sub|dub}&server={serverName}",
+			"hls_proxy":             "/api/proxy/hls?url={playlistUrl}&referer={referer}",
+			"subtitles":             "/api/subtitles?url={trackUrl}&format={vtt|srt|ass}&offset={seconds}",
+			"fallback_stats":        "/api/stats/fallback",
+			"debug_token":           "/api/debug/token?id={episodeId}&type={sub|dub}&server={serverName} (requires DEBUG=true)",
+			"estimated_schedule":    "/api/schedule?date={YYYY-MM-DD}&tz={timezone}&tzOffset={offset}",
+			"weekly_schedule":       "/api/schedule/week?date={YYYY-MM-DD}&tz={timezone}&tzOffset={offset}",
+			"schedule_calendar":     "/api/schedule.ics?ids={animeIds}&genres={genres}",
+			"anime_calendar":        "/api/anime/{id}/calendar.ics",
 			"next_episode_schedule": "/api/next-episode/{id}",
 			"health":                "/api/health",
 		},
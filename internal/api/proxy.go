@@ -0,0 +1,329 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/internal/hls"
+	"github.com/ayanrajpoot10/hianime-api/internal/subtitle"
+	"github.com/ayanrajpoot10/hianime-api/pkg/httpclient"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// proxyPassHeaders lists upstream response headers forwarded to the client
+var proxyPassHeaders = []string{
+	"Content-Type",
+	"Content-Length",
+	"Content-Range",
+	"Accept-Ranges",
+	"Cache-Control",
+	"Last-Modified",
+	"ETag",
+}
+
+// newProxyClient creates the client the proxy endpoints fetch upstream resources with.
+// Bodies are streamed to the client, so only connecting and waiting for the response
+// headers are bounded by the configured timeout, not the whole transfer.
+func newProxyClient(cfg *config.Config, hosts *hostAllowlist) *httpclient.Client {
+	client := httpclient.New(httpclient.Config{
+		UserAgent: cfg.UserAgent,
+		BaseURL:   cfg.BaseURL,
+		Retries:   cfg.MaxRetries,
+	})
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cfg.Timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cfg.Timeout
+	transport.ResponseHeaderTimeout = cfg.Timeout
+
+	underlying := client.GetUnderlyingClient()
+	underlying.Transport = transport
+	underlying.CheckRedirect = hosts.checkRedirect
+	return client
+}
+
+// proxyHLSURL builds a proxy URL for an upstream resource
+func proxyHLSURL(target, referer string) string {
+	params := url.Values{}
+	params.Set("url", target)
+	if referer != "" {
+		params.Set("referer", referer)
+	}
+	return "/api/proxy/hls?" + params.Encode()
+}
+
+// ProxyHLS handles GET /api/proxy/hls
+func (h *Handler) ProxyHLS(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	query := req.URL.Query()
+	target := query.Get("url")
+	if target == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	targetURL, status, err := h.proxyHosts.Parse(target)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	referer := query.Get("referer")
+
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, targetURL.String(), nil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if referer != "" {
+		upstreamReq.Header.Set("Referer", referer)
+		if refURL, err := url.Parse(referer); err == nil && refURL.Host != "" {
+			upstreamReq.Header.Set("Origin", refURL.Scheme+"://"+refURL.Host)
+		}
+	}
+
+	// Forward range requests for segments, playlists are always fetched whole
+	if !hls.IsPlaylist("", targetURL.String(), nil) {
+		if rangeHeader := req.Header.Get("Range"); rangeHeader != "" {
+			upstreamReq.Header.Set("Range", rangeHeader)
+		}
+		if ifRange := req.Header.Get("If-Range"); ifRange != "" {
+			upstreamReq.Header.Set("If-Range", ifRange)
+		}
+	}
+
+	resp, err := h.upstream.Do(upstreamReq)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to fetch upstream: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		writeError(w, resp.StatusCode, fmt.Errorf("upstream returned status code: %d", resp.StatusCode))
+		return
+	}
+
+	body := bufio.NewReader(resp.Body)
+	head, _ := body.Peek(16)
+
+	if hls.IsPlaylist(resp.Header.Get("Content-Type"), targetURL.String(), head) {
+		playlist, err := io.ReadAll(body)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Errorf("failed to read playlist: %w", err))
+			return
+		}
+
+		// Segments and variants of an allowed playlist may live on other CDN hosts
+		rewritten := hls.Rewrite(playlist, resp.Request.URL, func(absolute string) string {
+			h.proxyHosts.Learn(absolute)
+			return proxyHLSURL(absolute, referer)
+		})
+
+		w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		w.Header().Set("Content-Length", strconv.Itoa(len(rewritten)))
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		if req.Method != http.MethodHead {
+			w.Write(rewritten)
+		}
+		return
+	}
+
+	for _, name := range proxyPassHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			w.Header().Set(name, value)
+		}
+	}
+	w.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Range, Accept-Ranges")
+	w.WriteHeader(resp.StatusCode)
+
+	if req.Method != http.MethodHead {
+		io.Copy(w, body)
+	}
+}
+
+// Subtitles handles GET /api/subtitles
+func (h *Handler) Subtitles(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	query := req.URL.Query()
+	target := query.Get("url")
+	if target == "" {
+		writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+		return
+	}
+
+	targetURL, status, err := h.proxyHosts.Parse(target)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = subtitle.FormatWebVTT
+	}
+	if format == "ssa" {
+		format = subtitle.FormatASS
+	}
+	contentType, ok := subtitle.ContentTypes[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported subtitle format: %s", format))
+		return
+	}
+
+	// Offset is given in seconds and may be negative or fractional
+	var offset time.Duration
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		seconds, err := strconv.ParseFloat(offsetStr, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid offset: %s", offsetStr))
+			return
+		}
+		offset = time.Duration(seconds * float64(time.Second))
+	}
+
+	headers := map[string]string{}
+	if referer := query.Get("referer"); referer != "" {
+		headers["Referer"] = referer
+	}
+
+	resp, err := h.upstream.GetWithHeaders(targetURL.String(), headers)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to fetch subtitle: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	cues, err := subtitle.ParseVTT(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to parse subtitle: %w", err))
+		return
+	}
+
+	output, err := subtitle.Convert(subtitle.Shift(cues, offset), format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(output))
+}
+
+// StreamThumbnails handles GET /api/stream/thumbnails
+func (h *Handler) StreamThumbnails(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, http.ErrNotSupported)
+		return
+	}
+
+	query := req.URL.Query()
+	trackURL := query.Get("url")
+	referer := query.Get("referer")
+
+	// Resolve the thumbnails track from the episode stream when no track URL is given
+	if trackURL == "" {
+		episodeID := query.Get("id")
+		if episodeID == "" {
+			writeError(w, http.StatusBadRequest, http.ErrMissingFile)
+			return
+		}
+
+		serverType := query.Get("type")
+		if serverType == "" {
+			serverType = "sub"
+		}
+
+		serverName := query.Get("server")
+		if serverName == "" {
+			serverName = "HD-1"
+		}
+
+		stream, err := h.scraper.StreamLinks(episodeID, serverType, serverName, false)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, track := range stream.Tracks {
+			if track.Kind == "thumbnails" {
+				trackURL = track.File
+				break
+			}
+		}
+
+		if trackURL == "" {
+			writeError(w, http.StatusNotFound, fmt.Errorf("no thumbnails track found for episode: %s", episodeID))
+			return
+		}
+
+		if referer == "" {
+			referer = stream.Headers["Referer"]
+		}
+		h.proxyHosts.LearnStream(stream)
+	}
+
+	base, status, err := h.proxyHosts.Parse(trackURL)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+
+	headers := map[string]string{}
+	if referer != "" {
+		headers["Referer"] = referer
+	}
+
+	resp, err := h.upstream.GetWithHeaders(base.String(), headers)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to fetch thumbnails track: %w", err))
+		return
+	}
+	defer resp.Body.Close()
+
+	cues, err := subtitle.ParseVTT(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("failed to parse thumbnails track: %w", err))
+		return
+	}
+
+	response := &models.ThumbnailsResponse{
+		Track:      base.String(),
+		Sprites:    []string{},
+		Thumbnails: subtitle.ParseThumbnails(cues, base),
+	}
+
+	seen := make(map[string]bool)
+	for _, thumb := range response.Thumbnails {
+		if !seen[thumb.URL] {
+			seen[thumb.URL] = true
+			response.Sprites = append(response.Sprites, thumb.URL)
+			h.proxyHosts.Learn(thumb.URL)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
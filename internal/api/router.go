@@ -29,7 +29,7 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if r.config.EnableCORS {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Range")
 
 		if req.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -70,18 +70,54 @@ func (r *Router) route(w http.ResponseWriter, req *http.Request) {
 		r.handler.Servers(w, req)
 	case path == "/api/stream":
 		r.handler.Stream(w, req)
+	case path == "/api/stream/thumbnails":
+		r.handler.StreamThumbnails(w, req)
+	case path == "/api/proxy/hls":
+		r.handler.ProxyHLS(w, req)
+	case path == "/api/subtitles":
+		r.handler.Subtitles(w, req)
+	case path == "/api/stats/fallback":
+		r.handler.FallbackStats(w, req)
+	case path == "/api/debug/token":
+		r.handler.DebugToken(w, req)
+	case path == "/api/genres":
+		r.handler.Genres(w, req)
+	case path == "/api/categories":
+		r.handler.Categories(w, req)
+	case path == "/api/producers":
+		r.handler.Producers(w, req)
+	case path == "/api/resolve":
+		r.handler.Resolve(w, req)
 	case path == "/api/schedule":
 		r.handler.EstimatedSchedule(w, req)
+	case path == "/api/schedule/week":
+		r.handler.WeeklySchedule(w, req)
+	case path == "/api/schedule.ics":
+		r.handler.ScheduleCalendar(w, req)
 	case path == "/api/health":
 		r.handler.Health(w, req)
 
 	// Dynamic endpoints with path parameters
+	case strings.HasPrefix(path, "/api/anime/") && strings.HasSuffix(path, "/characters"):
+		r.handler.Characters(w, req)
+	case strings.HasPrefix(path, "/api/anime/") && strings.HasSuffix(path, "/franchise"):
+		r.handler.Franchise(w, req)
+	case strings.HasPrefix(path, "/api/anime/") && strings.HasSuffix(path, "/calendar.ics"):
+		r.handler.AnimeCalendar(w, req)
 	case strings.HasPrefix(path, "/api/anime/"):
 		r.handler.AnimeDetails(w, req)
+	case strings.HasPrefix(path, "/api/character/"):
+		r.handler.CharacterDetails(w, req)
+	case strings.HasPrefix(path, "/api/people/"):
+		r.handler.PersonDetails(w, req)
 	case strings.HasPrefix(path, "/api/qtip/"):
 		r.handler.AnimeQtipInfo(w, req)
 	case strings.HasPrefix(path, "/api/next-episode/"):
 		r.handler.NextEpisodeSchedule(w, req)
+	case strings.HasPrefix(path, "/api/skip-times/"):
+		r.handler.SkipTimes(w, req)
+	case strings.HasPrefix(path, "/api/watch/"):
+		r.handler.Watch(w, req)
 	case strings.HasPrefix(path, "/api/episodes/"):
 		r.handler.Episodes(w, req)
 	case strings.HasPrefix(path, "/api/animes/"):
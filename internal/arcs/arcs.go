@@ -0,0 +1,175 @@
+// Package arcs classifies episodes into story arcs and canon/filler types using a
+// bundled dataset that can be extended or overridden with a local JSON file.
+package arcs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// Episode types
+const (
+	TypeCanon      = "canon"
+	TypeMixed      = "mixed"
+	TypeFiller     = "filler"
+	TypeAnimeCanon = "anime_canon"
+)
+
+//go:embed data/arcs.json
+var bundled []byte
+
+// Range assigns a type to an inclusive range of episode numbers
+type Range struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Type  string `json:"type"`
+}
+
+// Arc is a named range of episodes. Its type applies to every episode in it
+// unless a more specific range in Anime.Types says otherwise.
+type Arc struct {
+	Name  string `json:"name"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Anime holds the arcs and episode types of one anime
+type Anime struct {
+	Title string  `json:"title,omitempty"`
+	Arcs  []Arc   `json:"arcs"`
+	Types []Range `json:"types,omitempty"`
+}
+
+// Dataset maps anime IDs to their arc data
+type Dataset map[string]*Anime
+
+// Load returns the bundled dataset with the entries of the JSON file at path
+// replacing bundled entries of the same anime. An empty path loads only the bundled data.
+func Load(path string) (Dataset, error) {
+	dataset := Dataset{}
+	if err := json.Unmarshal(bundled, &dataset); err != nil {
+		return nil, fmt.Errorf("failed to parse bundled arcs: %w", err)
+	}
+
+	if path == "" {
+		return dataset, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dataset, fmt.Errorf("failed to read arcs file: %w", err)
+	}
+
+	overrides := Dataset{}
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return dataset, fmt.Errorf("failed to parse arcs file: %w", err)
+	}
+
+	for id, anime := range overrides {
+		if anime == nil {
+			delete(dataset, id)
+			continue
+		}
+		dataset[id] = anime
+	}
+
+	return dataset, nil
+}
+
+// Classify sets the type and arc of every episode. Episodes inside an arc or type
+// range of the dataset take the dataset's word; all others, including every episode
+// of anime missing from the dataset, keep the site's filler flag.
+// It reports whether the dataset covered the anime.
+func (d Dataset) Classify(animeID string, episodes []models.EpisodeInfo) bool {
+	anime, ok := d[animeID]
+
+	for i := range episodes {
+		ep := &episodes[i]
+		ep.Type = siteType(*ep)
+		if !ok {
+			continue
+		}
+
+		covered := false
+		for _, arc := range anime.Arcs {
+			if ep.Episode >= arc.Start && ep.Episode <= arc.End {
+				covered = true
+				ep.Arc = arc.Name
+				ep.Type = TypeCanon
+				if arc.Type != "" {
+					ep.Type = arc.Type
+				}
+				break
+			}
+		}
+
+		for _, r := range anime.Types {
+			if ep.Episode >= r.Start && ep.Episode <= r.End {
+				covered = true
+				ep.Type = r.Type
+				break
+			}
+		}
+
+		if covered {
+			ep.IsFiller = ep.Type == TypeFiller
+		}
+	}
+
+	return ok
+}
+
+// siteType returns the type given by the site's filler flag
+func siteType(ep models.EpisodeInfo) string {
+	if ep.IsFiller {
+		return TypeFiller
+	}
+	return TypeCanon
+}
+
+// Group groups classified episodes by arc in episode order. Consecutive episodes
+// outside any arc are collected into unnamed groups.
+func Group(episodes []models.EpisodeInfo) []models.ArcGroup {
+	sorted := append([]models.EpisodeInfo(nil), episodes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Episode < sorted[j].Episode })
+
+	groups := []models.ArcGroup{}
+	for _, ep := range sorted {
+		if len(groups) == 0 || groups[len(groups)-1].Name != ep.Arc {
+			groups = append(groups, models.ArcGroup{
+				Name:   ep.Arc,
+				Start:  ep.Episode,
+				Counts: map[string]int{},
+			})
+		}
+
+		group := &groups[len(groups)-1]
+		group.End = ep.Episode
+		group.Counts[ep.Type]++
+		group.Episodes = append(group.Episodes, ep)
+	}
+
+	// An arc is filler or anime canon when every episode is, canon when none is
+	// filler or mixed
+	for i := range groups {
+		group := &groups[i]
+		switch {
+		case group.Counts[TypeFiller] == len(group.Episodes):
+			group.Type = TypeFiller
+		case group.Counts[TypeAnimeCanon] == len(group.Episodes):
+			group.Type = TypeAnimeCanon
+		case group.Counts[TypeFiller] == 0 && group.Counts[TypeMixed] == 0:
+			group.Type = TypeCanon
+		default:
+			group.Type = TypeMixed
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,143 @@
+package arcs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+func episodes(numbers ...int) []models.EpisodeInfo {
+	eps := make([]models.EpisodeInfo, len(numbers))
+	for i, n := range numbers {
+		eps[i] = models.EpisodeInfo{Episode: n}
+	}
+	return eps
+}
+
+func TestClassify(t *testing.T) {
+	dataset := Dataset{
+		"show-1": {
+			Arcs: []Arc{
+				{Name: "Opening", Start: 1, End: 3},
+				{Name: "Beach", Start: 4, End: 5, Type: TypeFiller},
+				{Name: "Original", Start: 6, End: 7, Type: TypeAnimeCanon},
+			},
+			Types: []Range{
+				{Start: 2, End: 2, Type: TypeMixed},
+				{Start: 5, End: 5, Type: TypeCanon},
+				{Start: 9, End: 9, Type: TypeFiller},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		animeID   string
+		episode   models.EpisodeInfo
+		wantType  string
+		wantArc   string
+		wantFill  bool
+		wantFound bool
+	}{
+		{"arc default canon", "show-1", models.EpisodeInfo{Episode: 1}, TypeCanon, "Opening", false, true},
+		{"arc overrides site filler flag", "show-1", models.EpisodeInfo{Episode: 3, IsFiller: true}, TypeCanon, "Opening", false, true},
+		{"types range overrides arc", "show-1", models.EpisodeInfo{Episode: 2}, TypeMixed, "Opening", false, true},
+		{"filler arc", "show-1", models.EpisodeInfo{Episode: 4}, TypeFiller, "Beach", true, true},
+		{"types range overrides filler arc", "show-1", models.EpisodeInfo{Episode: 5}, TypeCanon, "Beach", false, true},
+		{"anime canon arc", "show-1", models.EpisodeInfo{Episode: 6}, TypeAnimeCanon, "Original", false, true},
+		{"types range outside arcs", "show-1", models.EpisodeInfo{Episode: 9}, TypeFiller, "", true, true},
+		{"uncovered keeps site filler", "show-1", models.EpisodeInfo{Episode: 8, IsFiller: true}, TypeFiller, "", true, true},
+		{"uncovered keeps site canon", "show-1", models.EpisodeInfo{Episode: 10}, TypeCanon, "", false, true},
+		{"missing anime keeps site filler", "other-2", models.EpisodeInfo{Episode: 4, IsFiller: true}, TypeFiller, "", true, false},
+		{"missing anime keeps site canon", "other-2", models.EpisodeInfo{Episode: 1}, TypeCanon, "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eps := []models.EpisodeInfo{tt.episode}
+			if found := dataset.Classify(tt.animeID, eps); found != tt.wantFound {
+				t.Errorf("Classify() = %v, want %v", found, tt.wantFound)
+			}
+
+			ep := eps[0]
+			if ep.Type != tt.wantType || ep.Arc != tt.wantArc || ep.IsFiller != tt.wantFill {
+				t.Errorf("episode %d = {type %q, arc %q, filler %v}, want {type %q, arc %q, filler %v}",
+					ep.Episode, ep.Type, ep.Arc, ep.IsFiller, tt.wantType, tt.wantArc, tt.wantFill)
+			}
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	dataset := Dataset{
+		"show-1": {
+			Arcs: []Arc{
+				{Name: "Opening", Start: 1, End: 2},
+				{Name: "Beach", Start: 3, End: 4, Type: TypeFiller},
+				{Name: "Original", Start: 5, End: 6, Type: TypeAnimeCanon},
+				{Name: "Finale", Start: 7, End: 9},
+			},
+			Types: []Range{
+				{Start: 8, End: 8, Type: TypeFiller},
+			},
+		},
+	}
+
+	// Out of order input is grouped in episode order
+	eps := episodes(10, 1, 2, 3, 4, 5, 6, 7, 8, 9, 11)
+	dataset.Classify("show-1", eps)
+
+	type group struct {
+		name       string
+		start, end int
+		typ        string
+		counts     map[string]int
+	}
+	want := []group{
+		{"Opening", 1, 2, TypeCanon, map[string]int{TypeCanon: 2}},
+		{"Beach", 3, 4, TypeFiller, map[string]int{TypeFiller: 2}},
+		{"Original", 5, 6, TypeAnimeCanon, map[string]int{TypeAnimeCanon: 2}},
+		{"Finale", 7, 9, TypeMixed, map[string]int{TypeCanon: 2, TypeFiller: 1}},
+		{"", 10, 11, TypeCanon, map[string]int{TypeCanon: 2}},
+	}
+
+	groups := Group(eps)
+	if len(groups) != len(want) {
+		t.Fatalf("Group() returned %d groups, want %d", len(groups), len(want))
+	}
+	for i, g := range groups {
+		got := group{g.Name, g.Start, g.End, g.Type, g.Counts}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("group %d = %+v, want %+v", i, got, want[i])
+		}
+		if len(g.Episodes) != g.End-g.Start+1 {
+			t.Errorf("group %q has %d episodes, want %d", g.Name, len(g.Episodes), g.End-g.Start+1)
+		}
+	}
+}
+
+func TestGroupMixedWithAnimeCanon(t *testing.T) {
+	eps := []models.EpisodeInfo{
+		{Episode: 1, Arc: "Arc", Type: TypeAnimeCanon},
+		{Episode: 2, Arc: "Arc", Type: TypeCanon},
+	}
+
+	if groups := Group(eps); groups[0].Type != TypeCanon {
+		t.Errorf("Group() type = %q, want %q", groups[0].Type, TypeCanon)
+	}
+}
+
+func TestGroupMissingAnime(t *testing.T) {
+	eps := episodes(1, 2, 3)
+	eps[1].IsFiller = true
+	Dataset{}.Classify("other-2", eps)
+
+	groups := Group(eps)
+	if len(groups) != 1 {
+		t.Fatalf("Group() returned %d groups, want 1", len(groups))
+	}
+	if groups[0].Name != "" || groups[0].Type != TypeMixed {
+		t.Errorf("group = {name %q, type %q}, want unnamed mixed group", groups[0].Name, groups[0].Type)
+	}
+}
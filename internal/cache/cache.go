@@ -0,0 +1,63 @@
+// Package cache provides a small in-memory cache with per-entry expiry.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// entry holds a cached value and its expiry time
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache is a concurrency-safe map whose entries expire after a fixed TTL
+type Cache[V any] struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]entry[V]
+	nextSweep time.Time
+}
+
+// New creates a cache whose entries live for ttl
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		ttl:       ttl,
+		entries:   make(map[string]entry[V]),
+		nextSweep: time.Now().Add(ttl),
+	}
+}
+
+// Get returns the value stored under key if it has not expired
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key. Expired entries are swept at most once per TTL, so
+// inserts stay cheap and the map holds at most two TTLs' worth of entries.
+func (c *Cache[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.After(c.nextSweep) {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+
+	c.entries[key] = entry[V]{value: value, expires: now.Add(c.ttl)}
+}
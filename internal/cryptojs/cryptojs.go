@@ -0,0 +1,309 @@
+// Package cryptojs implements AES-CBC encryption compatible with CryptoJS and
+// "openssl enc", covering the "Salted__" passphrase format with either the
+// EVP_BytesToKey or PBKDF2 key derivation as well as raw key/IV mode.
+package cryptojs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// saltedPrefix marks passphrase encrypted data in the OpenSSL format
+const saltedPrefix = "Salted__"
+
+// Key derivation functions
+const (
+	KDFEVP    = "evp"
+	KDFPBKDF2 = "pbkdf2"
+	KDFRaw    = "raw"
+)
+
+// Scheme describes how the AES key and IV are obtained
+type Scheme struct {
+	// KDF is one of KDFEVP, KDFPBKDF2 or KDFRaw
+	KDF string
+	// Hash is the digest used by the KDF: md5, sha1, sha256 or sha512
+	Hash string
+	// Iterations is the number of KDF rounds
+	Iterations int
+	// KeySize is the AES key length in bytes: 16, 24 or 32
+	KeySize int
+	// Key and IV are used as is in raw mode
+	Key []byte
+	IV  []byte
+}
+
+// EVP is the scheme used by CryptoJS.AES with a passphrase and by "openssl enc -md md5"
+var EVP = Scheme{KDF: KDFEVP, Hash: "md5", Iterations: 1, KeySize: 32}
+
+// PBKDF2 is the scheme used by "openssl enc -pbkdf2" as configured by megacloud
+var PBKDF2 = Scheme{KDF: KDFPBKDF2, Hash: "sha1", Iterations: 1000, KeySize: 32}
+
+// Raw returns a scheme using key and iv directly
+func Raw(key, iv []byte) Scheme {
+	return Scheme{KDF: KDFRaw, Key: key, IV: iv, KeySize: len(key)}
+}
+
+// DefaultSchemes lists the passphrase schemes tried by Detect when none are given
+func DefaultSchemes() []Scheme {
+	return []Scheme{
+		PBKDF2,
+		EVP,
+		{KDF: KDFEVP, Hash: "sha256", Iterations: 1, KeySize: 32},
+		{KDF: KDFPBKDF2, Hash: "sha256", Iterations: 10000, KeySize: 32},
+		{KDF: KDFPBKDF2, Hash: "sha512", Iterations: 10000, KeySize: 32},
+	}
+}
+
+// String describes a scheme, e.g. "pbkdf2-sha1-1000"
+func (s Scheme) String() string {
+	if s.KDF == KDFRaw {
+		return fmt.Sprintf("raw-aes%d", len(s.Key)*8)
+	}
+	return fmt.Sprintf("%s-%s-%d", s.KDF, s.Hash, s.Iterations)
+}
+
+// withDefaults fills unset fields with the defaults of the scheme's KDF
+func (s Scheme) withDefaults() Scheme {
+	if s.KDF == "" {
+		s.KDF = KDFEVP
+	}
+	if s.Hash == "" {
+		s.Hash = "md5"
+		if s.KDF == KDFPBKDF2 {
+			s.Hash = "sha1"
+		}
+	}
+	if s.Iterations < 1 {
+		s.Iterations = 1
+		if s.KDF == KDFPBKDF2 {
+			s.Iterations = 1000
+		}
+	}
+	if s.KeySize == 0 {
+		s.KeySize = 32
+	}
+	return s
+}
+
+// newHash returns the hash constructor for a digest name
+func newHash(name string) (func() hash.Hash, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "-", "")) {
+	case "md5":
+		return md5.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported hash: %s", name)
+}
+
+// DeriveKeyIV derives the AES key and IV from a passphrase and salt
+func DeriveKeyIV(passphrase, salt []byte, scheme Scheme) ([]byte, []byte, error) {
+	scheme = scheme.withDefaults()
+
+	if scheme.KDF == KDFRaw {
+		return scheme.Key, scheme.IV, nil
+	}
+
+	newHashFunc, err := newHash(scheme.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := scheme.KeySize + aes.BlockSize
+
+	switch scheme.KDF {
+	case KDFPBKDF2:
+		keyIV := pbkdf2.Key(passphrase, salt, scheme.Iterations, size, newHashFunc)
+		return keyIV[:scheme.KeySize], keyIV[scheme.KeySize:], nil
+
+	case KDFEVP:
+		keyIV := evpBytesToKey(passphrase, salt, scheme.Iterations, size, newHashFunc)
+		return keyIV[:scheme.KeySize], keyIV[scheme.KeySize:], nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported key derivation: %s", scheme.KDF)
+}
+
+// evpBytesToKey implements OpenSSL's EVP_BytesToKey: D_i = H^count(D_{i-1} || pass || salt)
+func evpBytesToKey(passphrase, salt []byte, iterations, size int, newHashFunc func() hash.Hash) []byte {
+	var derived, block []byte
+	for len(derived) < size {
+		h := newHashFunc()
+		h.Write(block)
+		h.Write(passphrase)
+		h.Write(salt)
+		block = h.Sum(nil)
+
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(block)
+			block = h.Sum(nil)
+		}
+
+		derived = append(derived, block...)
+	}
+	return derived[:size]
+}
+
+// Encrypt encrypts plaintext and returns it base64 encoded. Passphrase schemes
+// produce the "Salted__" format with a random salt, raw mode the bare ciphertext.
+func Encrypt(plaintext []byte, passphrase string, scheme Scheme) (string, error) {
+	var salt []byte
+	if scheme.withDefaults().KDF != KDFRaw {
+		salt = make([]byte, 8)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+	return EncryptWithSalt(plaintext, passphrase, salt, scheme)
+}
+
+// EncryptWithSalt is Encrypt with a fixed salt, producing deterministic output for fixtures
+func EncryptWithSalt(plaintext []byte, passphrase string, salt []byte, scheme Scheme) (string, error) {
+	scheme = scheme.withDefaults()
+	if scheme.KDF != KDFRaw && len(salt) != 8 {
+		return "", fmt.Errorf("salt must be 8 bytes")
+	}
+
+	key, iv, err := DeriveKeyIV([]byte(passphrase), salt, scheme)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := newCipher(key, iv)
+	if err != nil {
+		return "", err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte{}, plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	if scheme.KDF != KDFRaw {
+		ciphertext = append(append([]byte(saltedPrefix), salt...), ciphertext...)
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt decrypts base64 encoded data produced by Encrypt, CryptoJS or openssl
+func Decrypt(encrypted, passphrase string, scheme Scheme) ([]byte, error) {
+	scheme = scheme.withDefaults()
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	var salt []byte
+	if scheme.KDF != KDFRaw {
+		if len(data) < 16 || string(data[:8]) != saltedPrefix {
+			return nil, fmt.Errorf("invalid encrypted data format")
+		}
+		salt = data[8:16]
+		data = data[16:]
+	}
+
+	key, iv, err := DeriveKeyIV([]byte(passphrase), salt, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := newCipher(key, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("ciphertext is not a multiple of block size")
+	}
+
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+	return Unpad(plaintext)
+}
+
+// Unpad removes and checks the PKCS7 padding of decrypted AES data. A wrong key
+// almost always leaves invalid padding, so it is reported as an error.
+func Unpad(plaintext []byte) ([]byte, error) {
+	if len(plaintext) == 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	for i := len(plaintext) - padding; i < len(plaintext); i++ {
+		if plaintext[i] != byte(padding) {
+			return nil, fmt.Errorf("invalid padding")
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// Detect tries each candidate scheme (DefaultSchemes when none are given) and
+// returns the first whose plaintext passes valid, e.g. LooksLikeJSON. Padding
+// alone matches a wrong key about once in 256 tries, so a shape check is needed.
+func Detect(encrypted, passphrase string, valid func([]byte) bool, candidates ...Scheme) (Scheme, []byte, error) {
+	if len(candidates) == 0 {
+		candidates = DefaultSchemes()
+	}
+
+	for _, scheme := range candidates {
+		plaintext, err := Decrypt(encrypted, passphrase, scheme)
+		if err != nil {
+			continue
+		}
+		if valid == nil || valid(plaintext) {
+			return scheme.withDefaults(), plaintext, nil
+		}
+	}
+
+	return Scheme{}, nil, fmt.Errorf("no matching encryption scheme")
+}
+
+// LooksLikeJSON reports whether plaintext is a JSON object or array
+func LooksLikeJSON(plaintext []byte) bool {
+	trimmed := bytes.TrimSpace(plaintext)
+	if len(trimmed) < 2 {
+		return false
+	}
+
+	first, last := trimmed[0], trimmed[len(trimmed)-1]
+	return (first == '{' && last == '}') || (first == '[' && last == ']')
+}
+
+// newCipher validates the key and IV sizes and creates the AES block cipher
+func newCipher(key, iv []byte) (cipher.Block, error) {
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid IV length: %d", len(iv))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return block, nil
+}
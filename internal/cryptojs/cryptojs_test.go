@@ -0,0 +1,139 @@
+package cryptojs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	plaintext := []byte(`{"sources":[{"file":"https://example.com/master.m3u8"}]}`)
+	key := bytes.Repeat([]byte{0x2a}, 32)
+	iv := bytes.Repeat([]byte{0x07}, 16)
+
+	tests := []struct {
+		name   string
+		scheme Scheme
+	}{
+		{"evp md5", EVP},
+		{"evp sha256", Scheme{KDF: KDFEVP, Hash: "sha256", Iterations: 1, KeySize: 32}},
+		{"evp aes128", Scheme{KDF: KDFEVP, Hash: "md5", Iterations: 1, KeySize: 16}},
+		{"pbkdf2 sha1 1", Scheme{KDF: KDFPBKDF2, Hash: "sha1", Iterations: 1, KeySize: 32}},
+		{"pbkdf2 sha1 1000", PBKDF2},
+		{"pbkdf2 sha1 10000", Scheme{KDF: KDFPBKDF2, Hash: "sha1", Iterations: 10000, KeySize: 32}},
+		{"pbkdf2 sha256 1000", Scheme{KDF: KDFPBKDF2, Hash: "sha256", Iterations: 1000, KeySize: 32}},
+		{"pbkdf2 sha256 10000", Scheme{KDF: KDFPBKDF2, Hash: "sha256", Iterations: 10000, KeySize: 32}},
+		{"pbkdf2 sha512 10000", Scheme{KDF: KDFPBKDF2, Hash: "sha512", Iterations: 10000, KeySize: 32}},
+		{"raw aes256", Raw(key, iv)},
+		{"raw aes128", Raw(key[:16], iv)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := Encrypt(plaintext, "secret", tt.scheme)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			decrypted, err := Decrypt(encrypted, "secret", tt.scheme)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+
+			if tt.scheme.KDF != KDFRaw {
+				if got, err := Decrypt(encrypted, "wrong", tt.scheme); err == nil && bytes.Equal(got, plaintext) {
+					t.Errorf("Decrypt() with wrong passphrase returned the plaintext")
+				}
+			}
+		})
+	}
+}
+
+// The vectors were produced with OpenSSL 3.0 from the plaintext below, e.g.
+// printf '%s' "$plaintext" | openssl enc -aes-256-cbc -pbkdf2 -iter 1000 -md sha1 -pass pass:secret -a -A
+func TestOpenSSLVectors(t *testing.T) {
+	plaintext := []byte(`{"sources":"https://example.com/master.m3u8"}`)
+
+	tests := []struct {
+		name      string
+		encrypted string
+		scheme    Scheme
+	}{
+		{
+			name:      "enc -md md5",
+			encrypted: "U2FsdGVkX1920iyQw/cpPFmMGNdlG5Z7HJp7e7xl7I81eG+KL81jJ41fFnbQnCJ3TLGutmz028CNZjezM/CVAg==",
+			scheme:    EVP,
+		},
+		{
+			name:      "enc -pbkdf2 -iter 1000 -md sha1",
+			encrypted: "U2FsdGVkX1+nQ4ZYVykzCqiYZIEjjQs+KiDKvwEy8uab4UMp4MQkFg2cQO3DKB9i8H6b6lIKIcY+81frcbs1Bw==",
+			scheme:    PBKDF2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decrypted, err := Decrypt(tt.encrypted, "secret", tt.scheme)
+			if err != nil {
+				t.Fatalf("Decrypt() error = %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("Decrypt() = %q, want %q", decrypted, plaintext)
+			}
+
+			// Encrypting with the same salt must reproduce OpenSSL's output
+			data, _ := base64.StdEncoding.DecodeString(tt.encrypted)
+			encrypted, err := EncryptWithSalt(plaintext, "secret", data[8:16], tt.scheme)
+			if err != nil {
+				t.Fatalf("EncryptWithSalt() error = %v", err)
+			}
+			if encrypted != tt.encrypted {
+				t.Errorf("EncryptWithSalt() = %q, want %q", encrypted, tt.encrypted)
+			}
+		})
+	}
+}
+
+// Produced with: openssl enc -aes-128-cbc -K 000102030405060708090a0b0c0d0e0f -iv 0f0e0d0c0b0a09080706050403020100 -a -A
+func TestOpenSSLRawVector(t *testing.T) {
+	plaintext := []byte(`{"sources":"https://example.com/master.m3u8"}`)
+	key := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	iv := []byte{0x0f, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a, 0x09, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0x00}
+	const encrypted = "tVi6UfyjeNlC9JISHCiuxhmmg0vpVTHmoNzq62h+jT6ZS0VgFN+XZSJM+xfFGmhs"
+
+	decrypted, err := Decrypt(encrypted, "", Raw(key, iv))
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypt() = %q, want %q", decrypted, plaintext)
+	}
+
+	if got, err := Encrypt(plaintext, "", Raw(key, iv)); err != nil || got != encrypted {
+		t.Errorf("Encrypt() = %q, %v, want %q", got, err, encrypted)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	plaintext := []byte(`{"sources":[]}`)
+	scheme := Scheme{KDF: KDFPBKDF2, Hash: "sha256", Iterations: 10000, KeySize: 32}
+
+	encrypted, err := Encrypt(plaintext, "secret", scheme)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	detected, decrypted, err := Detect(encrypted, "secret", LooksLikeJSON)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+	if detected.String() != scheme.String() {
+		t.Errorf("Detect() scheme = %s, want %s", detected, scheme)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Detect() plaintext = %q, want %q", decrypted, plaintext)
+	}
+}
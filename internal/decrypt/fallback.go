@@ -0,0 +1,218 @@
+package decrypt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+var dataIDRegex = regexp.MustCompile(`data-id=["'](\d+)["']`)
+
+// hostStats records fallback outcomes per host for the lifetime of the process
+var hostStats = struct {
+	sync.Mutex
+	hosts map[string]*models.FallbackHostStats
+}{hosts: make(map[string]*models.FallbackHostStats)}
+
+// fallbackResult holds the stream found on a fallback host
+type fallbackResult struct {
+	host    string
+	file    string
+	referer string
+	data    map[string]any
+}
+
+// fallbackHosts returns the configured hosts serving serverName, in order
+func (md *MegacloudDecryptor) fallbackHosts(serverName string) []config.FallbackHost {
+	var hosts []config.FallbackHost
+	for _, host := range md.config.FallbackHosts {
+		if host.Host == "" {
+			continue
+		}
+		if len(host.Servers) > 0 && !containsFold(host.Servers, serverName) {
+			continue
+		}
+		if containsFold(host.ExcludeServers, serverName) {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// tryFallbacks tries every fallback host for the server in order and returns the first stream found
+func (md *MegacloudDecryptor) tryFallbacks(selectedServer *models.Server, epID string) (*fallbackResult, error) {
+	hosts := md.fallbackHosts(selectedServer.Name)
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("no fallback hosts configured for %s", selectedServer.Name)
+	}
+
+	var errs []string
+	for _, host := range hosts {
+		result, err := md.tryFallbackHost(host, selectedServer, epID)
+		recordFallback(host.Host, err)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", host.Host, err))
+	}
+
+	return nil, fmt.Errorf("fallback failed: %s", strings.Join(errs, "; "))
+}
+
+// tryFallbackHost fetches the stream page and sources from a single fallback host
+func (md *MegacloudDecryptor) tryFallbackHost(host config.FallbackHost, selectedServer *models.Server, epID string) (*fallbackResult, error) {
+	defaults := config.FallbackTemplate()
+	if host.StreamPath == "" {
+		host.StreamPath = defaults.StreamPath
+	}
+	if host.SourcesPath == "" {
+		host.SourcesPath = defaults.SourcesPath
+	}
+	if host.Referer == "" {
+		host.Referer = defaults.Referer
+	}
+	if host.StreamReferer == "" {
+		host.StreamReferer = defaults.StreamReferer
+	}
+
+	values := map[string]string{
+		"host":    host.Host,
+		"episode": epID,
+		"type":    selectedServer.Type,
+	}
+
+	streamURL := "https://" + host.Host + expandTemplate(host.StreamPath, values)
+	headers := map[string]string{
+		"Referer": expandTemplate(host.Referer, values),
+	}
+
+	resp, err := md.client.GetWithHeaders(streamURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	htmlBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fallback response: %w", err)
+	}
+
+	dataIDMatch := dataIDRegex.FindStringSubmatch(string(htmlBytes))
+	if len(dataIDMatch) < 2 {
+		return nil, fmt.Errorf("could not extract data-id for fallback")
+	}
+	values["id"] = dataIDMatch[1]
+
+	sourcesURL := "https://" + host.Host + expandTemplate(host.SourcesPath, values)
+	headers = map[string]string{
+		"X-Requested-With": "XMLHttpRequest",
+	}
+
+	resp2, err := md.client.GetWithHeaders(sourcesURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("fallback sources request failed: %w", err)
+	}
+	defer resp2.Body.Close()
+
+	var fallbackData map[string]any
+	if err := json.NewDecoder(resp2.Body).Decode(&fallbackData); err != nil {
+		return nil, fmt.Errorf("failed to decode fallback data: %w", err)
+	}
+
+	sources, _ := fallbackData["sources"].(map[string]any)
+	file, _ := sources["file"].(string)
+	if file == "" {
+		return nil, fmt.Errorf("no file in fallback sources")
+	}
+
+	return &fallbackResult{
+		host:    host.Host,
+		file:    file,
+		referer: expandTemplate(host.StreamReferer, values),
+		data:    fallbackData,
+	}, nil
+}
+
+// recordFallback updates the success statistics of a fallback host
+func recordFallback(host string, err error) {
+	hostStats.Lock()
+	defer hostStats.Unlock()
+
+	stats, ok := hostStats.hosts[host]
+	if !ok {
+		stats = &models.FallbackHostStats{Host: host}
+		hostStats.hosts[host] = stats
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	stats.Attempts++
+	if err != nil {
+		stats.Failures++
+		stats.LastError = err.Error()
+		stats.LastFailure = now
+	} else {
+		stats.Successes++
+		stats.LastSuccess = now
+	}
+	stats.SuccessRate = float64(stats.Successes) / float64(stats.Attempts)
+}
+
+// FallbackStats returns the success statistics of every fallback host, including
+// configured hosts that have not been tried yet and hosts no longer configured
+func FallbackStats(cfg *config.Config) []models.FallbackHostStats {
+	hostStats.Lock()
+	defer hostStats.Unlock()
+
+	seen := make(map[string]bool)
+	var result []models.FallbackHostStats
+	for _, host := range cfg.FallbackHosts {
+		if host.Host == "" || seen[host.Host] {
+			continue
+		}
+		seen[host.Host] = true
+
+		stats := models.FallbackHostStats{Host: host.Host}
+		if recorded, ok := hostStats.hosts[host.Host]; ok {
+			stats = *recorded
+		}
+		stats.Enabled = true
+		result = append(result, stats)
+	}
+
+	var retired []models.FallbackHostStats
+	for host, stats := range hostStats.hosts {
+		if !seen[host] {
+			retired = append(retired, *stats)
+		}
+	}
+	sort.Slice(retired, func(i, j int) bool { return retired[i].Host < retired[j].Host })
+
+	return append(result, retired...)
+}
+
+// expandTemplate replaces {name} placeholders with values
+func expandTemplate(template string, values map[string]string) string {
+	for key, value := range values {
+		template = strings.ReplaceAll(template, "{"+key+"}", value)
+	}
+	return template
+}
+
+// containsFold reports whether list contains value, ignoring case
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"net/url"
 	"regexp"
 	"strings"
 
 	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/internal/subtitle"
 	"github.com/ayanrajpoot10/hianime-api/pkg/httpclient"
 	"github.com/ayanrajpoot10/hianime-api/pkg/models"
 )
@@ -37,9 +40,6 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 	}
 	epID := epParts[1]
 
-	fallback1 := "megaplay.buzz"
-	fallback2 := "vidwish.live"
-
 	// Fetch sources data and decryption key concurrently
 	type sourcesResult struct {
 		data map[string]any
@@ -110,28 +110,32 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 		return nil, fmt.Errorf("missing link in sourcesData")
 	}
 
-	// Extract source ID from link
-	sourceIDRegex := regexp.MustCompile(`/([^/?]+)\?`)
-	sourceIDMatch := sourceIDRegex.FindStringSubmatch(ajaxLink)
-	if len(sourceIDMatch) < 2 {
-		return nil, fmt.Errorf("unable to extract sourceId from link")
-	}
-	sourceID := sourceIDMatch[1]
-
-	// Extract base URL
-	baseURLRegex := regexp.MustCompile(`^(https?://[^/]+(?:/[^/]+){3})`)
-	baseURLMatch := baseURLRegex.FindStringSubmatch(ajaxLink)
-	if len(baseURLMatch) < 2 {
-		return nil, fmt.Errorf("could not extract base URL from ajaxLink")
+	baseURL, sourceID, err := parseEmbedLink(ajaxLink)
+	if err != nil {
+		return nil, err
 	}
-	baseURL := baseURLMatch[1]
 
 	var decryptedSources []map[string]any
 	var rawSourceData map[string]any
 
+	// Streams served from the embed host expect its origin as referer
+	referer := ""
+	if u, err := url.Parse(ajaxLink); err == nil && u.Host != "" {
+		referer = fmt.Sprintf("%s://%s/", u.Scheme, u.Host)
+	}
+
+	fallbackHost := ""
+
 	// Try main decryption method
-	tokenURL := fmt.Sprintf("%s/%s?k=1&autoPlay=0&oa=0&asi=1", baseURL, sourceID)
-	token, tokenErr := md.tokenExtractor.ExtractToken(tokenURL)
+	tokenURL := embedTokenURL(baseURL, sourceID)
+	token, strategy, tokenErr := md.tokenExtractor.ExtractToken(tokenURL)
+	if md.config.EnableDebug {
+		if tokenErr != nil {
+			log.Printf("token extraction failed for %s: %v", tokenURL, tokenErr)
+		} else {
+			log.Printf("token for %s found by %s strategy", tokenURL, strategy)
+		}
+	}
 
 	if tokenErr == nil {
 		// Get sources with token
@@ -152,84 +156,28 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 		}
 	}
 
-	// If main method failed, try fallback
+	// If main method failed, try the configured fallback hosts
 	if len(decryptedSources) == 0 {
-		fallback := fallback1
-		if strings.EqualFold(selectedServer.Name, "hd-1") {
-			fallback = fallback1
-		} else {
-			fallback = fallback2
-		}
-
-		fallbackURL := fmt.Sprintf("https://%s/stream/s-2/%s/%s", fallback, epID, selectedServer.Type)
-		headers := map[string]string{
-			"Referer": fmt.Sprintf("https://%s/", fallback1),
-		}
-
-		resp, err := md.client.GetWithHeaders(fallbackURL, headers)
-		if err != nil {
-			return nil, fmt.Errorf("fallback failed: %w", err)
-		}
-		defer resp.Body.Close()
-
-		// Read HTML response
-		htmlBytes, err := io.ReadAll(resp.Body)
+		fallback, err := md.tryFallbacks(selectedServer, epID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read fallback response: %w", err)
+			return nil, err
 		}
-		html := string(htmlBytes)
 
-		// Extract data-id
-		dataIDRegex := regexp.MustCompile(`data-id=["'](\d+)["']`)
-		dataIDMatch := dataIDRegex.FindStringSubmatch(html)
-		if len(dataIDMatch) < 2 {
-			return nil, fmt.Errorf("could not extract data-id for fallback")
-		}
-		realID := dataIDMatch[1]
-
-		// Get fallback sources
-		fallbackSourcesURL := fmt.Sprintf("https://%s/stream/getSources?id=%s", fallback, realID)
-		headers = map[string]string{
-			"X-Requested-With": "XMLHttpRequest",
-		}
-
-		resp2, err := md.client.GetWithHeaders(fallbackSourcesURL, headers)
-		if err != nil {
-			return nil, fmt.Errorf("fallback sources request failed: %w", err)
-		}
-		defer resp2.Body.Close()
-
-		var fallbackData map[string]any
-		if err := json.NewDecoder(resp2.Body).Decode(&fallbackData); err != nil {
-			return nil, fmt.Errorf("failed to decode fallback data: %w", err)
-		}
-
-		// Extract file URL from fallback
-		if sources, ok := fallbackData["sources"].(map[string]any); ok {
-			if file, ok := sources["file"].(string); ok {
-				decryptedSources = []map[string]any{
-					{"file": file},
-				}
-			}
+		decryptedSources = []map[string]any{
+			{"file": fallback.file},
 		}
+		referer = fallback.referer
+		fallbackHost = fallback.host
 
 		// Use fallback data for tracks, intro, outro if main data is empty
 		if rawSourceData == nil {
 			rawSourceData = make(map[string]any)
 		}
-		if rawSourceData["tracks"] == nil {
-			if tracks, ok := fallbackData["tracks"]; ok {
-				rawSourceData["tracks"] = tracks
-			}
-		}
-		if rawSourceData["intro"] == nil {
-			if intro, ok := fallbackData["intro"]; ok {
-				rawSourceData["intro"] = intro
-			}
-		}
-		if rawSourceData["outro"] == nil {
-			if outro, ok := fallbackData["outro"]; ok {
-				rawSourceData["outro"] = outro
+		for _, field := range []string{"tracks", "intro", "outro", "recap", "credits"} {
+			if rawSourceData[field] == nil || (field != "tracks" && parseTimeRange(rawSourceData[field]).IsEmpty()) {
+				if value, ok := fallback.data[field]; ok {
+					rawSourceData[field] = value
+				}
 			}
 		}
 	}
@@ -240,9 +188,10 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 
 	// Build response
 	response := &models.StreamResponse{
-		ID:     id,
-		Type:   selectedServer.Type,
-		Server: selectedServer.Name,
+		ID:       id,
+		Type:     selectedServer.Type,
+		Server:   selectedServer.Name,
+		Fallback: fallbackHost,
 	}
 
 	// Set main stream link
@@ -253,6 +202,13 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 		}
 	}
 
+	// Set headers required to fetch the stream
+	if referer != "" {
+		response.Headers = map[string]string{
+			"Referer": referer,
+		}
+	}
+
 	// Set tracks
 	if tracks, ok := rawSourceData["tracks"].([]any); ok {
 		for _, track := range tracks {
@@ -264,34 +220,109 @@ func (md *MegacloudDecryptor) Decrypt(selectedServer *models.Server, id string)
 				if kind, ok := trackMap["kind"].(string); ok {
 					t.Kind = kind
 				}
+				if label, ok := trackMap["label"].(string); ok {
+					t.Label = label
+				}
+				if isDefault, ok := trackMap["default"].(bool); ok {
+					t.Default = isDefault
+				}
+				if t.Kind == "captions" || t.Kind == "subtitles" {
+					t.Language = subtitle.LanguageCode(t.Label, t.File)
+				}
 				response.Tracks = append(response.Tracks, t)
 			}
 		}
 	}
 
-	// Set intro
-	if intro, ok := rawSourceData["intro"].(map[string]any); ok {
-		tr := &models.TimeRange{}
-		if start, ok := intro["start"].(float64); ok {
-			tr.Start = int(start)
-		}
-		if end, ok := intro["end"].(float64); ok {
-			tr.End = int(end)
-		}
-		response.Intro = tr
+	// Set skip markers
+	response.Intro = parseTimeRange(rawSourceData["intro"])
+	response.Outro = parseTimeRange(rawSourceData["outro"])
+	if recap := parseTimeRange(rawSourceData["recap"]); !recap.IsEmpty() {
+		response.Recap = recap
 	}
-
-	// Set outro
-	if outro, ok := rawSourceData["outro"].(map[string]any); ok {
-		tr := &models.TimeRange{}
-		if start, ok := outro["start"].(float64); ok {
-			tr.Start = int(start)
-		}
-		if end, ok := outro["end"].(float64); ok {
-			tr.End = int(end)
-		}
-		response.Outro = tr
+	if credits := parseTimeRange(rawSourceData["credits"]); !credits.IsEmpty() {
+		response.Credits = credits
 	}
 
 	return response, nil
 }
+
+// parseTimeRange converts a {start, end} object from the sources data into a time range
+func parseTimeRange(value any) *models.TimeRange {
+	data, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	tr := &models.TimeRange{}
+	if start, ok := data["start"].(float64); ok {
+		tr.Start = int(start)
+	}
+	if end, ok := data["end"].(float64); ok {
+		tr.End = int(end)
+	}
+	return tr
+}
+
+// InspectToken lists every token candidate found on the embed page of a server
+func (md *MegacloudDecryptor) InspectToken(selectedServer *models.Server) (*models.TokenDiagnostics, error) {
+	url := fmt.Sprintf("%s/ajax/v2/episode/sources?id=%s", md.config.BaseURL, selectedServer.ID)
+	resp, err := md.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sources: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var sourcesData struct {
+		Link string `json:"link"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sourcesData); err != nil {
+		return nil, fmt.Errorf("failed to decode sources: %w", err)
+	}
+	if sourcesData.Link == "" {
+		return nil, fmt.Errorf("missing link in sourcesData")
+	}
+
+	baseURL, sourceID, err := parseEmbedLink(sourcesData.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics, err := md.tokenExtractor.Inspect(embedTokenURL(baseURL, sourceID))
+	if err != nil {
+		return nil, err
+	}
+	diagnostics.Server = selectedServer.Name
+
+	return diagnostics, nil
+}
+
+// InspectTokenURL lists every token candidate found on an embed page
+func (md *MegacloudDecryptor) InspectTokenURL(embedURL string) (*models.TokenDiagnostics, error) {
+	return md.tokenExtractor.Inspect(embedURL)
+}
+
+var (
+	sourceIDRegex  = regexp.MustCompile(`/([^/?]+)\?`)
+	embedBaseRegex = regexp.MustCompile(`^(https?://[^/]+(?:/[^/]+){3})`)
+)
+
+// parseEmbedLink extracts the embed base URL and source ID from an episode sources link
+func parseEmbedLink(ajaxLink string) (string, string, error) {
+	sourceIDMatch := sourceIDRegex.FindStringSubmatch(ajaxLink)
+	if len(sourceIDMatch) < 2 {
+		return "", "", fmt.Errorf("unable to extract sourceId from link")
+	}
+
+	baseURLMatch := embedBaseRegex.FindStringSubmatch(ajaxLink)
+	if len(baseURLMatch) < 2 {
+		return "", "", fmt.Errorf("could not extract base URL from ajaxLink")
+	}
+
+	return baseURLMatch[1], sourceIDMatch[1], nil
+}
+
+// embedTokenURL returns the embed page URL the token is extracted from
+func embedTokenURL(baseURL, sourceID string) string {
+	return fmt.Sprintf("%s/%s?k=1&autoPlay=0&oa=0&asi=1", baseURL, sourceID)
+}
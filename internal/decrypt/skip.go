@@ -0,0 +1,70 @@
+package decrypt
+
+import (
+	"strings"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// SkipSources returns the skip markers a server reports for an episode. The main
+// flow is always used; when it lacks an intro or outro the fallback hosts are
+// asked as well, since mirrors often carry markers the main source is missing.
+func (md *MegacloudDecryptor) SkipSources(selectedServer *models.Server, id string) []models.SkipSource {
+	main := models.SkipSource{
+		Server: selectedServer.Name,
+		Type:   selectedServer.Type,
+		Path:   "main",
+	}
+
+	response, err := md.Decrypt(selectedServer, id)
+	if err != nil {
+		main.Error = err.Error()
+		return []models.SkipSource{main}
+	}
+
+	if response.Fallback != "" {
+		main.Path = "fallback:" + response.Fallback
+	}
+	main.Intro = nonEmpty(response.Intro)
+	main.Outro = nonEmpty(response.Outro)
+	main.Recap = response.Recap
+	main.Credits = response.Credits
+
+	sources := []models.SkipSource{main}
+	if response.Fallback != "" || (main.Intro != nil && main.Outro != nil) {
+		return sources
+	}
+
+	epParts := strings.Split(id, "ep=")
+	if len(epParts) != 2 {
+		return sources
+	}
+
+	fallback := models.SkipSource{
+		Server: selectedServer.Name,
+		Type:   selectedServer.Type,
+		Path:   "fallback",
+	}
+
+	result, err := md.tryFallbacks(selectedServer, epParts[1])
+	if err != nil {
+		fallback.Error = err.Error()
+		return append(sources, fallback)
+	}
+
+	fallback.Path = "fallback:" + result.host
+	fallback.Intro = nonEmpty(parseTimeRange(result.data["intro"]))
+	fallback.Outro = nonEmpty(parseTimeRange(result.data["outro"]))
+	fallback.Recap = nonEmpty(parseTimeRange(result.data["recap"]))
+	fallback.Credits = nonEmpty(parseTimeRange(result.data["credits"]))
+
+	return append(sources, fallback)
+}
+
+// nonEmpty returns tr, or nil when it has no duration
+func nonEmpty(tr *models.TimeRange) *models.TimeRange {
+	if tr.IsEmpty() {
+		return nil
+	}
+	return tr
+}
@@ -9,8 +9,36 @@ import (
 
 	"github.com/ayanrajpoot10/hianime-api/config"
 	"github.com/ayanrajpoot10/hianime-api/pkg/httpclient"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
 
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Token extraction strategies
+const (
+	StrategyMeta         = "meta"
+	StrategyDataDpi      = "dataDpi"
+	StrategyNonce        = "nonce"
+	StrategyWindowString = "windowString"
+	StrategyWindowObject = "windowObject"
+	StrategyComment      = "comment"
+)
+
+// DefaultTokenStrategies is the order in which strategies are tried when none is configured
+var DefaultTokenStrategies = []string{
+	StrategyMeta,
+	StrategyDataDpi,
+	StrategyNonce,
+	StrategyWindowString,
+	StrategyWindowObject,
+	StrategyComment,
+}
+
+var (
+	stringAssignRegex = regexp.MustCompile(`window\.(\w+)\s*=\s*["']([\w-]+)["']`)
+	objectAssignRegex = regexp.MustCompile(`window\.(\w+)\s*=\s*(\{[\s\S]*?\});`)
+	commentTokenRegex = regexp.MustCompile(`^_is_th:([\w-]+)$`)
 )
 
 // TokenExtractor handles token extraction functionality
@@ -27,110 +55,181 @@ func NewTokenExtractor(client *httpclient.Client, config *config.Config) *TokenE
 	}
 }
 
-// ExtractToken extracts various tokens and parameters from HTML pages
-func (te *TokenExtractor) ExtractToken(url string) (string, error) {
+// ExtractToken extracts the token from an embed page, returning it along with the
+// strategy that produced it. Strategies are tried in the configured order.
+func (te *TokenExtractor) ExtractToken(url string) (string, string, error) {
+	diagnostics, err := te.Inspect(url)
+	if err != nil {
+		return "", "", err
+	}
+
+	if diagnostics.Token == "" {
+		return "", "", fmt.Errorf("no token found")
+	}
+
+	return diagnostics.Token, diagnostics.Strategy, nil
+}
+
+// Inspect fetches an embed page and lists every token candidate found by every
+// strategy, together with the token the configured strategy order selects
+func (te *TokenExtractor) Inspect(url string) (*models.TokenDiagnostics, error) {
 	headers := map[string]string{
 		"Referer": te.config.BaseURL + "/",
 	}
 
 	resp, err := te.client.GetWithHeaders(url, headers)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	html, err := doc.Html()
+	page, err := doc.Html()
 	if err != nil {
-		return "", fmt.Errorf("failed to get HTML content: %w", err)
+		return nil, fmt.Errorf("failed to get HTML content: %w", err)
 	}
 
-	results := make(map[string]string)
+	order := te.strategies()
+	diagnostics := &models.TokenDiagnostics{
+		URL:        url,
+		Order:      order,
+		Candidates: []models.TokenCandidate{},
+	}
 
-	// 1. Meta tag
-	if meta, exists := doc.Find(`meta[name="_gg_fb"]`).Attr("content"); exists && meta != "" {
-		results["meta"] = meta
+	for _, strategy := range DefaultTokenStrategies {
+		diagnostics.Candidates = append(diagnostics.Candidates, findCandidates(strategy, doc, page)...)
 	}
 
-	// 2. Data attribute
-	if dpi, exists := doc.Find(`[data-dpi]`).Attr("data-dpi"); exists && dpi != "" {
-		results["dataDpi"] = dpi
+	// Pick the first non-empty candidate of the first strategy in order that found one
+	for _, strategy := range order {
+		for _, candidate := range diagnostics.Candidates {
+			if candidate.Strategy == strategy && candidate.Value != "" {
+				diagnostics.Token = candidate.Value
+				diagnostics.Strategy = strategy
+				return diagnostics, nil
+			}
+		}
 	}
 
-	// 3. Nonce from empty script
-	doc.Find("script[nonce]").Each(func(i int, sel *goquery.Selection) {
-		text := sel.Text()
-		if strings.Contains(text, "empty nonce script") {
-			if nonce, exists := sel.Attr("nonce"); exists && nonce != "" {
-				results["nonce"] = nonce
+	return diagnostics, nil
+}
+
+// strategies returns the configured strategy order, ignoring unknown names
+func (te *TokenExtractor) strategies() []string {
+	var order []string
+	for _, name := range te.config.TokenStrategies {
+		for _, known := range DefaultTokenStrategies {
+			if strings.EqualFold(name, known) {
+				order = append(order, known)
 			}
 		}
-	})
-
-	// 4. JS string assignment: window.<key> = "value";
-	stringAssignRegex := regexp.MustCompile(`window\.(\w+)\s*=\s*["']([\w-]+)["']`)
-	stringMatches := stringAssignRegex.FindAllStringSubmatch(html, -1)
-	for _, match := range stringMatches {
-		if len(match) >= 3 {
-			key := fmt.Sprintf("window.%s", match[1])
-			value := match[2]
-			results[key] = value
+	}
+
+	if len(order) == 0 {
+		return DefaultTokenStrategies
+	}
+	return order
+}
+
+// findCandidates runs a single strategy against the page, returning candidates in document order
+func findCandidates(strategy string, doc *goquery.Document, page string) []models.TokenCandidate {
+	var candidates []models.TokenCandidate
+	add := func(key, value string) {
+		if value != "" {
+			candidates = append(candidates, models.TokenCandidate{Strategy: strategy, Key: key, Value: value})
 		}
 	}
 
-	// 5. JS object assignment: window.<key> = { ... };
-	objectAssignRegex := regexp.MustCompile(`window\.(\w+)\s*=\s*(\{[\s\S]*?\});`)
-	objectMatches := objectAssignRegex.FindAllStringSubmatch(html, -1)
-	for _, match := range objectMatches {
-		if len(match) >= 3 {
-			varName := match[1]
-			rawObj := match[2]
-
-			// Try to parse as JSON
-			var parsedObj map[string]any
-			if err := json.Unmarshal([]byte(rawObj), &parsedObj); err == nil {
-				var stringValues []string
-				for _, val := range parsedObj {
-					if strVal, ok := val.(string); ok {
-						stringValues = append(stringValues, strVal)
-					}
-				}
-				concatenated := strings.Join(stringValues, "")
-				if len(concatenated) >= 20 {
-					key := fmt.Sprintf("window.%s", varName)
-					results[key] = concatenated
-				}
+	switch strategy {
+	case StrategyMeta:
+		doc.Find(`meta[name="_gg_fb"]`).Each(func(i int, sel *goquery.Selection) {
+			add("_gg_fb", sel.AttrOr("content", ""))
+		})
+
+	case StrategyDataDpi:
+		doc.Find(`[data-dpi]`).Each(func(i int, sel *goquery.Selection) {
+			add("data-dpi", sel.AttrOr("data-dpi", ""))
+		})
+
+	case StrategyNonce:
+		doc.Find("script[nonce]").Each(func(i int, sel *goquery.Selection) {
+			if strings.Contains(sel.Text(), "empty nonce script") {
+				add("nonce", sel.AttrOr("nonce", ""))
 			}
+		})
+
+	case StrategyWindowString:
+		// window.<key> = "value";
+		for _, match := range stringAssignRegex.FindAllStringSubmatch(page, -1) {
+			add("window."+match[1], match[2])
 		}
-	}
 
-	// 6. HTML comment: <!-- _is_th:... -->
-	doc.Contents().Each(func(i int, sel *goquery.Selection) {
-		for _, node := range sel.Nodes {
-			if node.Type == 8 { // Comment node
-				comment := strings.TrimSpace(node.Data)
-				commentRegex := regexp.MustCompile(`^_is_th:([\w-]+)$`)
-				if match := commentRegex.FindStringSubmatch(comment); match != nil {
-					results["commentToken"] = strings.TrimSpace(match[1])
-				}
+	case StrategyWindowObject:
+		// window.<key> = { ... }; with string values concatenated in source order
+		for _, match := range objectAssignRegex.FindAllStringSubmatch(page, -1) {
+			values, err := orderedStringValues(match[2])
+			if err != nil {
+				continue
+			}
+			if concatenated := strings.Join(values, ""); len(concatenated) >= 20 {
+				add("window."+match[1], concatenated)
 			}
 		}
-	})
 
-	// Return the first non-empty token found
-	for _, token := range results {
-		if token != "" {
-			return token, nil
+	case StrategyComment:
+		// <!-- _is_th:... -->
+		for _, node := range doc.Nodes {
+			walkComments(node, func(comment string) {
+				if match := commentTokenRegex.FindStringSubmatch(strings.TrimSpace(comment)); match != nil {
+					add("_is_th", strings.TrimSpace(match[1]))
+				}
+			})
+		}
+	}
+
+	return candidates
+}
+
+// walkComments calls fn for every comment node below node in document order
+func walkComments(node *html.Node, fn func(string)) {
+	if node.Type == html.CommentNode {
+		fn(node.Data)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		walkComments(child, fn)
+	}
+}
+
+// orderedStringValues decodes a flat JSON object and returns its string values in key order
+func orderedStringValues(raw string) ([]string, error) {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+
+	var values []string
+	for decoder.More() {
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		if str, ok := value.(string); ok {
+			values = append(values, str)
 		}
 	}
 
-	return "", fmt.Errorf("no token found")
+	return values, nil
 }
@@ -0,0 +1,386 @@
+package download
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/internal/cryptojs"
+	"github.com/ayanrajpoot10/hianime-api/internal/hls"
+	"github.com/ayanrajpoot10/hianime-api/pkg/httpclient"
+)
+
+// Options controls how an HLS stream is downloaded
+type Options struct {
+	Quality     string
+	Concurrency int
+	Retries     int
+	Headers     map[string]string
+	Progress    io.Writer
+}
+
+// manifestFile records which stream a parts directory belongs to
+const manifestFile = "manifest.json"
+
+// errFragmentedMP4 is returned for fMP4 streams, which can't be written as MPEG-TS
+var errFragmentedMP4 = errors.New("fragmented MP4 streams (EXT-X-MAP) are not supported")
+
+// partsManifest identifies the stream and variant a parts directory was downloaded
+// from. The playlist URL is kept without its query, as signed URLs change between
+// runs of the same stream.
+type partsManifest struct {
+	Playlist string `json:"playlist"`
+	Variant  string `json:"variant,omitempty"`
+	Segments int    `json:"segments"`
+}
+
+// Downloader assembles HLS streams into a single MPEG-TS file
+type Downloader struct {
+	client *httpclient.Client
+	opts   Options
+
+	keysMu sync.Mutex
+	keys   map[string]*segmentKey
+}
+
+// segmentKey is a decryption key fetched once and shared by the segment workers
+type segmentKey struct {
+	once sync.Once
+	key  []byte
+	err  error
+}
+
+// New creates a new downloader
+func New(cfg *config.Config, opts Options) *Downloader {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+
+	return &Downloader{
+		client: httpclient.New(httpclient.Config{
+			Timeout:   cfg.Timeout,
+			UserAgent: cfg.UserAgent,
+			BaseURL:   cfg.BaseURL,
+			// Retries are done by fetch, which also covers failed body reads
+			Retries: 0,
+		}),
+		opts: opts,
+		keys: make(map[string]*segmentKey),
+	}
+}
+
+// Download fetches the playlist at playlistURL, picks the configured variant
+// and writes all of its segments to output. Finished segments are kept in a
+// "<output>.parts" directory so an interrupted download resumes where it
+// stopped. Parts left by a different stream or variant are discarded.
+func (d *Downloader) Download(playlistURL, output string) error {
+	media, manifest, err := d.resolveMediaPlaylist(playlistURL)
+	if err != nil {
+		return err
+	}
+	if media.InitSegment != "" {
+		return errFragmentedMP4
+	}
+
+	partsDir := output + ".parts"
+	if err := preparePartsDir(partsDir, manifest); err != nil {
+		return err
+	}
+
+	progress := newProgressBar(d.opts.Progress, len(media.Segments))
+
+	// Collect segments still missing from previous runs
+	var pending []int
+	for i := range media.Segments {
+		if info, err := os.Stat(segmentPath(partsDir, i)); err == nil {
+			progress.resumed(info.Size())
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, len(pending))
+	var wg sync.WaitGroup
+
+	for w := 0; w < d.opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				size, err := d.downloadSegment(media.Segments[i], segmentPath(partsDir, i))
+				if err != nil {
+					errs <- fmt.Errorf("segment %d: %w", i, err)
+					continue
+				}
+				progress.done(size)
+			}
+		}()
+	}
+
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	progress.finish()
+
+	if err, ok := <-errs; ok {
+		return fmt.Errorf("download incomplete, rerun to resume: %w", err)
+	}
+
+	if err := d.assemble(media, partsDir, output); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(partsDir)
+}
+
+// preparePartsDir creates the parts directory, emptying it first when its manifest
+// doesn't match the stream being downloaded
+func preparePartsDir(partsDir string, manifest partsManifest) error {
+	var existing partsManifest
+	if data, err := os.ReadFile(filepath.Join(partsDir, manifestFile)); err == nil {
+		if json.Unmarshal(data, &existing) != nil || existing != manifest {
+			if err := os.RemoveAll(partsDir); err != nil {
+				return fmt.Errorf("failed to discard stale parts: %w", err)
+			}
+		}
+	} else if _, err := os.Stat(partsDir); err == nil {
+		// Parts without a manifest can't be matched to a stream
+		if err := os.RemoveAll(partsDir); err != nil {
+			return fmt.Errorf("failed to discard stale parts: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(partsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create parts directory: %w", err)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to encode parts manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(partsDir, manifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write parts manifest: %w", err)
+	}
+
+	return nil
+}
+
+// resolveMediaPlaylist fetches a playlist, following a master playlist to the selected
+// variant, and returns the manifest identifying the stream that was picked
+func (d *Downloader) resolveMediaPlaylist(playlistURL string) (*hls.MediaPlaylist, partsManifest, error) {
+	var manifest partsManifest
+
+	body, base, err := d.fetch(playlistURL)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("failed to fetch playlist: %w", err)
+	}
+
+	if hls.IsMaster(body) {
+		variants, err := hls.ParseMaster(body, base)
+		if err != nil {
+			return nil, manifest, err
+		}
+
+		variant, err := hls.SelectVariant(variants, d.opts.Quality)
+		if err != nil {
+			return nil, manifest, err
+		}
+		manifest.Variant = fmt.Sprintf("%s@%d", variant.Resolution, variant.Bandwidth)
+
+		if d.opts.Progress != nil {
+			fmt.Fprintf(d.opts.Progress, "Selected variant %s (%d kbps)\n", variant.Resolution, variant.Bandwidth/1000)
+		}
+
+		body, base, err = d.fetch(variant.URI)
+		if err != nil {
+			return nil, manifest, fmt.Errorf("failed to fetch media playlist: %w", err)
+		}
+	}
+
+	media, err := hls.ParseMedia(body, base)
+	if err != nil {
+		return nil, manifest, err
+	}
+
+	manifest.Playlist = base.Scheme + "://" + base.Host + base.Path
+	manifest.Segments = len(media.Segments)
+	return media, manifest, nil
+}
+
+// downloadSegment downloads, decrypts and stores a single segment
+func (d *Downloader) downloadSegment(segment hls.Segment, path string) (int64, error) {
+	data, _, err := d.fetch(segment.URI)
+	if err != nil {
+		return 0, err
+	}
+
+	if segment.Key != nil {
+		if data, err = d.decrypt(segment, data); err != nil {
+			return 0, err
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write segment: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, fmt.Errorf("failed to store segment: %w", err)
+	}
+
+	return int64(len(data)), nil
+}
+
+// decrypt decrypts an AES-128 encrypted segment
+func (d *Downloader) decrypt(segment hls.Segment, data []byte) ([]byte, error) {
+	if segment.Key.Method != "AES-128" {
+		return nil, fmt.Errorf("unsupported encryption method: %s", segment.Key.Method)
+	}
+
+	key, err := d.key(segment.Key.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an explicit IV the media sequence number is used
+	iv := segment.Key.IV
+	if len(iv) == 0 {
+		iv = make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(iv[8:], uint64(segment.Sequence))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	if len(iv) != aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("invalid encrypted segment")
+	}
+
+	plaintext := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+	plaintext, err = cryptojs.Unpad(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt segment: %w", err)
+	}
+	return plaintext, nil
+}
+
+// key fetches and caches a segment decryption key. Each key is fetched once, and
+// workers only wait for the keys they need; a failed fetch is retried by the next
+// segment that uses the key.
+func (d *Downloader) key(uri string) ([]byte, error) {
+	d.keysMu.Lock()
+	k, ok := d.keys[uri]
+	if !ok {
+		k = &segmentKey{}
+		d.keys[uri] = k
+	}
+	d.keysMu.Unlock()
+
+	k.once.Do(func() {
+		k.key, k.err = d.fetchKey(uri)
+	})
+
+	if k.err != nil {
+		d.keysMu.Lock()
+		if d.keys[uri] == k {
+			delete(d.keys, uri)
+		}
+		d.keysMu.Unlock()
+	}
+	return k.key, k.err
+}
+
+// fetchKey downloads a segment decryption key
+func (d *Downloader) fetchKey(uri string) ([]byte, error) {
+	key, _, err := d.fetch(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch key: %w", err)
+	}
+	if len(key) != 16 {
+		return nil, fmt.Errorf("invalid key length: %d", len(key))
+	}
+	return key, nil
+}
+
+// assemble concatenates all downloaded segments into output
+func (d *Downloader) assemble(media *hls.MediaPlaylist, partsDir, output string) error {
+	tmp := output + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	for i := range media.Segments {
+		part, err := os.Open(segmentPath(partsDir, i))
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("missing segment %d: %w", i, err)
+		}
+		_, err = io.Copy(file, part)
+		part.Close()
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return os.Rename(tmp, output)
+}
+
+// fetch downloads a resource with the configured headers, retrying failed transfers
+func (d *Downloader) fetch(rawURL string) ([]byte, *url.URL, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= d.opts.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		resp, err := d.client.GetWithHeaders(rawURL, d.opts.Headers)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("failed to read response: %w", err)
+			continue
+		}
+
+		return data, resp.Request.URL, nil
+	}
+
+	return nil, nil, lastErr
+}
+
+// segmentPath returns the file used to store segment i
+func segmentPath(dir string, i int) string {
+	return filepath.Join(dir, fmt.Sprintf("%06d.ts", i))
+}
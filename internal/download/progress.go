@@ -0,0 +1,96 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressWidth = 30
+
+// progressBar renders segment download progress on a single terminal line
+type progressBar struct {
+	mu           sync.Mutex
+	out          io.Writer
+	total        int
+	finished     int
+	bytes        int64
+	resumedBytes int64
+	started      time.Time
+}
+
+// newProgressBar creates a progress bar, a nil writer disables rendering
+func newProgressBar(out io.Writer, total int) *progressBar {
+	return &progressBar{
+		out:     out,
+		total:   total,
+		started: time.Now(),
+	}
+}
+
+// done records a finished segment of the given size
+func (p *progressBar) done(size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finished++
+	p.bytes += size
+	p.render()
+}
+
+// resumed records a segment kept from a previous run. Its size counts towards the
+// total but not towards the download speed.
+func (p *progressBar) resumed(size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finished++
+	p.bytes += size
+	p.resumedBytes += size
+	p.render()
+}
+
+// finish terminates the progress line
+func (p *progressBar) finish() {
+	if p.out != nil {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// render draws the current state of the progress bar
+func (p *progressBar) render() {
+	if p.out == nil || p.total == 0 {
+		return
+	}
+
+	ratio := float64(p.finished) / float64(p.total)
+	filled := int(ratio * progressWidth)
+
+	speed := 0.0
+	if elapsed := time.Since(p.started).Seconds(); elapsed > 0 {
+		speed = float64(p.bytes-p.resumedBytes) / elapsed
+	}
+
+	fmt.Fprintf(p.out, "\r[%s%s] %3.0f%% %d/%d segments %s %s/s",
+		strings.Repeat("#", filled),
+		strings.Repeat(" ", progressWidth-filled),
+		ratio*100,
+		p.finished,
+		p.total,
+		formatBytes(float64(p.bytes)),
+		formatBytes(speed),
+	)
+}
+
+// formatBytes formats a byte count with a binary unit suffix
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB"}
+	i := 0
+	for n >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
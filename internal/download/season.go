@@ -0,0 +1,361 @@
+package download
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/config"
+	"github.com/ayanrajpoot10/hianime-api/internal/scraper"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// DefaultTemplate is the file name template used when none is configured
+const DefaultTemplate = "{title} - S01E{ep:02} [{type}].ts"
+
+// Episode download states recorded in the state file
+const (
+	StatusPending = "pending"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// SeasonOptions controls a batch season download
+type SeasonOptions struct {
+	Episodes    string
+	SkipFillers bool
+	Subtitles   bool
+	Template    string
+	Dir         string
+	StateFile   string
+	Type        string
+	Server      string
+	Quality     string
+	Concurrency int
+	Retries     int
+	Progress    io.Writer
+}
+
+// EpisodeState records the download progress of a single episode
+type EpisodeState struct {
+	Episode   int       `json:"episode"`
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	File      string    `json:"file,omitempty"`
+	Subtitles []string  `json:"subtitles,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SeasonState is persisted as JSON so an interrupted run can resume
+type SeasonState struct {
+	AnimeID  string                   `json:"animeId"`
+	Title    string                   `json:"title"`
+	Episodes map[string]*EpisodeState `json:"episodes"`
+}
+
+// SeasonDownloader downloads a range of episodes of an anime
+type SeasonDownloader struct {
+	config  *config.Config
+	scraper *scraper.Scraper
+	opts    SeasonOptions
+}
+
+// NewSeasonDownloader creates a new season downloader
+func NewSeasonDownloader(cfg *config.Config, s *scraper.Scraper, opts SeasonOptions) *SeasonDownloader {
+	if opts.Template == "" {
+		opts.Template = DefaultTemplate
+	}
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+
+	return &SeasonDownloader{
+		config:  cfg,
+		scraper: s,
+		opts:    opts,
+	}
+}
+
+// Download walks the episode list of animeID and downloads every selected
+// episode, skipping the ones already marked done in the state file
+func (sd *SeasonDownloader) Download(animeID string) (*SeasonState, error) {
+	selected, err := ParseEpisodeRanges(sd.opts.Episodes)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes, err := sd.scraper.Episodes(animeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get episodes: %w", err)
+	}
+
+	title := animeID
+	if details, err := sd.scraper.AnimeDetails(animeID); err == nil && details.Title != "" {
+		title = details.Title
+	}
+
+	if err := os.MkdirAll(sd.opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	statePath := sd.opts.StateFile
+	if statePath == "" {
+		statePath = filepath.Join(sd.opts.Dir, animeID+".state.json")
+	}
+
+	state, err := LoadState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	state.AnimeID = animeID
+	state.Title = title
+
+	var failed int
+	for _, ep := range episodes.Episodes {
+		if !selected(ep.Episode) || (sd.opts.SkipFillers && ep.IsFiller) {
+			continue
+		}
+
+		key := strconv.Itoa(ep.Episode)
+		epState := state.Episodes[key]
+		if epState != nil && epState.Status == StatusDone {
+			if _, err := os.Stat(filepath.Join(sd.opts.Dir, epState.File)); err == nil {
+				sd.logf("Episode %d already downloaded, skipping\n", ep.Episode)
+				continue
+			}
+		}
+
+		epState = &EpisodeState{
+			Episode: ep.Episode,
+			ID:      ep.ID,
+			Status:  StatusPending,
+		}
+		state.Episodes[key] = epState
+		if err := state.Save(statePath); err != nil {
+			return state, err
+		}
+
+		if err := sd.downloadEpisode(title, ep, epState); err != nil {
+			failed++
+			epState.Status = StatusFailed
+			epState.Error = err.Error()
+			sd.logf("Episode %d failed: %v\n", ep.Episode, err)
+		} else {
+			epState.Status = StatusDone
+			epState.Error = ""
+		}
+
+		epState.UpdatedAt = time.Now()
+		if err := state.Save(statePath); err != nil {
+			return state, err
+		}
+	}
+
+	if failed > 0 {
+		return state, fmt.Errorf("%d episode(s) failed, rerun to resume", failed)
+	}
+
+	return state, nil
+}
+
+// downloadEpisode downloads the video and subtitle tracks of one episode
+func (sd *SeasonDownloader) downloadEpisode(title string, ep models.EpisodeInfo, epState *EpisodeState) error {
+	sd.logf("Downloading episode %d: %s\n", ep.Episode, ep.Title)
+
+	stream, err := sd.scraper.StreamLinks(ep.ID, sd.opts.Type, sd.opts.Server, sd.config.VerifyStreams)
+	if err != nil {
+		return fmt.Errorf("failed to get stream links: %w", err)
+	}
+
+	name := FormatFilename(sd.opts.Template, map[string]any{
+		"title":         title,
+		"anime":         title,
+		"ep":            ep.Episode,
+		"episode":       ep.Episode,
+		"episode_title": ep.Title,
+		"type":          sd.opts.Type,
+		"server":        sd.opts.Server,
+		"quality":       sd.opts.Quality,
+	})
+	epState.File = name
+
+	downloader := New(sd.config, Options{
+		Quality:     sd.opts.Quality,
+		Concurrency: sd.opts.Concurrency,
+		Retries:     sd.opts.Retries,
+		Headers:     stream.Headers,
+		Progress:    sd.opts.Progress,
+	})
+
+	if err := downloader.Download(stream.Link.File, filepath.Join(sd.opts.Dir, name)); err != nil {
+		return err
+	}
+
+	if !sd.opts.Subtitles {
+		return nil
+	}
+
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	epState.Subtitles = nil
+	for i, track := range stream.Tracks {
+		if track.Kind != "captions" && track.Kind != "subtitles" {
+			continue
+		}
+
+		lang := track.Language
+		if lang == "" {
+			lang = sanitizeFilename(strings.ToLower(track.Label))
+		}
+		if lang == "" {
+			lang = strconv.Itoa(i)
+		}
+
+		data, _, err := downloader.fetch(track.File)
+		if err != nil {
+			sd.logf("Failed to download %s subtitles: %v\n", lang, err)
+			continue
+		}
+
+		subName := fmt.Sprintf("%s.%s.vtt", base, lang)
+		if err := os.WriteFile(filepath.Join(sd.opts.Dir, subName), data, 0644); err != nil {
+			return fmt.Errorf("failed to write subtitles: %w", err)
+		}
+		epState.Subtitles = append(epState.Subtitles, subName)
+	}
+
+	return nil
+}
+
+// logf writes progress messages when a progress writer is configured
+func (sd *SeasonDownloader) logf(format string, args ...any) {
+	if sd.opts.Progress != nil {
+		fmt.Fprintf(sd.opts.Progress, format, args...)
+	}
+}
+
+// LoadState reads a season state file, returning an empty state if it does not exist
+func LoadState(path string) (*SeasonState, error) {
+	state := &SeasonState{
+		Episodes: make(map[string]*EpisodeState),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	if state.Episodes == nil {
+		state.Episodes = make(map[string]*EpisodeState)
+	}
+
+	return state, nil
+}
+
+// Save writes the state file atomically
+func (st *SeasonState) Save(path string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// ParseEpisodeRanges parses a selection like "1-12,15" into a predicate.
+// An empty selection matches every episode.
+func ParseEpisodeRanges(spec string) (func(int) bool, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "all" {
+		return func(int) bool { return true }, nil
+	}
+
+	type span struct{ from, to int }
+	var spans []span
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid episode range: %s", part)
+		}
+
+		to := from
+		if isRange {
+			if strings.TrimSpace(toStr) == "" {
+				to = int(^uint(0) >> 1)
+			} else if to, err = strconv.Atoi(strings.TrimSpace(toStr)); err != nil {
+				return nil, fmt.Errorf("invalid episode range: %s", part)
+			}
+		}
+
+		if to < from {
+			return nil, fmt.Errorf("invalid episode range: %s", part)
+		}
+		spans = append(spans, span{from, to})
+	}
+
+	return func(ep int) bool {
+		for _, s := range spans {
+			if ep >= s.from && ep <= s.to {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// placeholderRegex matches template placeholders like {title} or {ep:02}
+var placeholderRegex = regexp.MustCompile(`\{(\w+)(?::(\d+))?\}`)
+
+// FormatFilename expands a file name template. Numeric values honour a
+// zero-padded width such as {ep:02}; unknown placeholders are left as is.
+func FormatFilename(template string, values map[string]any) string {
+	name := placeholderRegex.ReplaceAllStringFunc(template, func(match string) string {
+		groups := placeholderRegex.FindStringSubmatch(match)
+		value, ok := values[strings.ToLower(groups[1])]
+		if !ok {
+			return match
+		}
+
+		if n, isInt := value.(int); isInt {
+			width, _ := strconv.Atoi(groups[2])
+			return fmt.Sprintf("%0*d", width, n)
+		}
+
+		return fmt.Sprint(value)
+	})
+
+	return sanitizeFilename(name)
+}
+
+// sanitizeFilename removes characters that are invalid in file names
+func sanitizeFilename(name string) string {
+	replacer := strings.NewReplacer(
+		"/", "-", "\\", "-", ":", "-", "*", "", "?", "",
+		"\"", "'", "<", "", ">", "", "|", "-",
+	)
+	return strings.TrimSpace(replacer.Replace(name))
+}
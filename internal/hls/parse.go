@@ -0,0 +1,258 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Variant represents a stream entry of a master playlist
+type Variant struct {
+	URI        string
+	Bandwidth  int
+	Width      int
+	Height     int
+	Codecs     string
+	Resolution string
+}
+
+// Key represents an EXT-X-KEY declaration
+type Key struct {
+	Method string
+	URI    string
+	IV     []byte
+}
+
+// Segment represents a media segment of a media playlist
+type Segment struct {
+	URI      string
+	Duration float64
+	Sequence int
+	Key      *Key
+}
+
+// MediaPlaylist represents a parsed media playlist
+type MediaPlaylist struct {
+	TargetDuration int
+	MediaSequence  int
+	InitSegment    string
+	Segments       []Segment
+	EndList        bool
+}
+
+// IsMaster reports whether a playlist body is a master playlist
+func IsMaster(body []byte) bool {
+	return bytes.Contains(body, []byte("#EXT-X-STREAM-INF"))
+}
+
+// ParseMaster parses the variant streams of a master playlist, resolving
+// their URIs against base. Variants are sorted by descending bandwidth.
+func ParseMaster(body []byte, base *url.URL) ([]Variant, error) {
+	var variants []Variant
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	var pending *Variant
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#EXT-X-STREAM-INF:") {
+			attrs := ParseAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			v := &Variant{
+				Codecs:     attrs["CODECS"],
+				Resolution: attrs["RESOLUTION"],
+			}
+			v.Bandwidth, _ = strconv.Atoi(attrs["BANDWIDTH"])
+			if w, h, ok := strings.Cut(v.Resolution, "x"); ok {
+				v.Width, _ = strconv.Atoi(w)
+				v.Height, _ = strconv.Atoi(h)
+			}
+			pending = v
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if pending != nil {
+			pending.URI = ResolveURI(base, line)
+			variants = append(variants, *pending)
+			pending = nil
+		}
+	}
+
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("no variant streams found in master playlist")
+	}
+
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Bandwidth > variants[j].Bandwidth
+	})
+
+	return variants, nil
+}
+
+// ParseMedia parses a media playlist, resolving segment, key and init
+// segment URIs against base
+func ParseMedia(body []byte, base *url.URL) (*MediaPlaylist, error) {
+	playlist := &MediaPlaylist{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var key *Key
+	var duration float64
+	sequence := -1
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+			playlist.TargetDuration, _ = strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-TARGETDURATION:"))
+		case strings.HasPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"):
+			playlist.MediaSequence, _ = strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-MEDIA-SEQUENCE:"))
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			attrs := ParseAttributes(strings.TrimPrefix(line, "#EXT-X-MAP:"))
+			if uri := attrs["URI"]; uri != "" {
+				playlist.InitSegment = ResolveURI(base, uri)
+			}
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			attrs := ParseAttributes(strings.TrimPrefix(line, "#EXT-X-KEY:"))
+			if attrs["METHOD"] == "" || attrs["METHOD"] == "NONE" {
+				key = nil
+				continue
+			}
+			key = &Key{
+				Method: attrs["METHOD"],
+				URI:    ResolveURI(base, attrs["URI"]),
+			}
+			if iv := attrs["IV"]; iv != "" {
+				decoded, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(iv, "0x"), "0X"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid key IV: %s", iv)
+				}
+				key.IV = decoded
+			}
+		case strings.HasPrefix(line, "#EXTINF:"):
+			value := strings.TrimPrefix(line, "#EXTINF:")
+			if idx := strings.Index(value, ","); idx >= 0 {
+				value = value[:idx]
+			}
+			duration, _ = strconv.ParseFloat(strings.TrimSpace(value), 64)
+		case line == "#EXT-X-ENDLIST":
+			playlist.EndList = true
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if sequence < 0 {
+				sequence = playlist.MediaSequence
+			}
+			playlist.Segments = append(playlist.Segments, Segment{
+				URI:      ResolveURI(base, line),
+				Duration: duration,
+				Sequence: sequence,
+				Key:      key,
+			})
+			sequence++
+			duration = 0
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+
+	if len(playlist.Segments) == 0 {
+		return nil, fmt.Errorf("no segments found in media playlist")
+	}
+
+	return playlist, nil
+}
+
+// ParseAttributes parses an attribute list like `BANDWIDTH=1,CODECS="a,b"`
+func ParseAttributes(list string) map[string]string {
+	attrs := make(map[string]string)
+
+	for len(list) > 0 {
+		eq := strings.Index(list, "=")
+		if eq < 0 {
+			break
+		}
+		name := strings.TrimSpace(list[:eq])
+		list = list[eq+1:]
+
+		var value string
+		if strings.HasPrefix(list, `"`) {
+			end := strings.Index(list[1:], `"`)
+			if end < 0 {
+				value = list[1:]
+				list = ""
+			} else {
+				value = list[1 : end+1]
+				list = list[end+2:]
+			}
+			list = strings.TrimPrefix(list, ",")
+		} else {
+			comma := strings.Index(list, ",")
+			if comma < 0 {
+				value = list
+				list = ""
+			} else {
+				value = list[:comma]
+				list = list[comma+1:]
+			}
+		}
+
+		attrs[strings.ToUpper(name)] = strings.TrimSpace(value)
+	}
+
+	return attrs
+}
+
+// SelectVariant picks the variant matching quality ("1080p", "720", "best",
+// "worst"). When no exact height matches, the best variant not exceeding the
+// requested height is used, falling back to the lowest available one.
+func SelectVariant(variants []Variant, quality string) (Variant, error) {
+	if len(variants) == 0 {
+		return Variant{}, fmt.Errorf("no variants available")
+	}
+
+	quality = strings.ToLower(strings.TrimSpace(quality))
+	switch quality {
+	case "", "best", "highest":
+		return variants[0], nil
+	case "worst", "lowest":
+		return variants[len(variants)-1], nil
+	}
+
+	height, err := strconv.Atoi(strings.TrimSuffix(quality, "p"))
+	if err != nil {
+		return Variant{}, fmt.Errorf("invalid quality: %s", quality)
+	}
+
+	for _, v := range variants {
+		if v.Height == height {
+			return v, nil
+		}
+	}
+
+	for _, v := range variants {
+		if v.Height > 0 && v.Height <= height {
+			return v, nil
+		}
+	}
+
+	return variants[len(variants)-1], nil
+}
@@ -0,0 +1,98 @@
+package hls
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// uriAttrRegex matches URI="..." attributes inside playlist tags
+var uriAttrRegex = regexp.MustCompile(`URI="([^"]*)"`)
+
+// uriTags lists the tags whose URI attribute references another resource
+var uriTags = []string{
+	"#EXT-X-KEY",
+	"#EXT-X-SESSION-KEY",
+	"#EXT-X-MAP",
+	"#EXT-X-MEDIA",
+	"#EXT-X-I-FRAME-STREAM-INF",
+	"#EXT-X-PART",
+	"#EXT-X-PRELOAD-HINT",
+	"#EXT-X-RENDITION-REPORT",
+}
+
+// IsPlaylist reports whether a response looks like an HLS playlist based on
+// its content type, its URL or the first bytes of its body
+func IsPlaylist(contentType, rawURL string, head []byte) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.Contains(contentType, "mpegurl") {
+		return true
+	}
+
+	if u, err := url.Parse(rawURL); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".m3u8") {
+		return true
+	}
+
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("#EXTM3U"))
+}
+
+// ResolveURI resolves a playlist URI against the playlist's own URL
+func ResolveURI(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if base == nil {
+		return ref
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	return base.ResolveReference(u).String()
+}
+
+// Rewrite resolves every URI in a media or master playlist against base and
+// replaces it with the result of mapURI. Segment and variant lines as well as
+// URI attributes of keys, init segments and renditions are rewritten.
+func Rewrite(body []byte, base *url.URL, mapURI func(absolute string) string) []byte {
+	var out bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			out.WriteString(line)
+		case strings.HasPrefix(trimmed, "#"):
+			if hasURIAttr(trimmed) {
+				line = uriAttrRegex.ReplaceAllStringFunc(line, func(attr string) string {
+					match := uriAttrRegex.FindStringSubmatch(attr)
+					return `URI="` + mapURI(ResolveURI(base, match[1])) + `"`
+				})
+			}
+			out.WriteString(line)
+		default:
+			out.WriteString(mapURI(ResolveURI(base, trimmed)))
+		}
+
+		out.WriteByte('\n')
+	}
+
+	return out.Bytes()
+}
+
+// hasURIAttr checks if a tag line may carry a URI attribute
+func hasURIAttr(line string) bool {
+	for _, tag := range uriTags {
+		if strings.HasPrefix(line, tag+":") {
+			return true
+		}
+	}
+	return false
+}
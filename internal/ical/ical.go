@@ -0,0 +1,105 @@
+// Package ical renders calendar events as an iCalendar (RFC 5545) feed.
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+// ContentType is the MIME type of a rendered calendar
+const ContentType = "text/calendar; charset=utf-8"
+
+// prodID identifies the application that produced the calendar
+const prodID = "-//hianime-api//Airing Schedule//EN"
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+// timestampLayout is the UTC date-time form used for every timestamp
+const timestampLayout = "20060102T150405Z"
+
+// Event is a single calendar entry. Calendar apps replace an event with a
+// later one carrying the same UID instead of adding a duplicate.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+}
+
+// Calendar is a named list of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Render returns the calendar in iCalendar format with CRLF line endings
+func (c *Calendar) Render() string {
+	var b strings.Builder
+	stamp := time.Now().UTC().Format(timestampLayout)
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.UID))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART:"+event.Start.UTC().Format(timestampLayout))
+		writeLine(&b, "DTEND:"+event.End.UTC().Format(timestampLayout))
+		writeLine(&b, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(&b, "URL:"+event.URL)
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// escapeText escapes the characters that are special in TEXT values
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeLine writes a content line, folding it so no line exceeds 75 octets.
+// Lines are only split between UTF-8 characters.
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// isRuneStart reports whether a byte begins a UTF-8 encoded character
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,28 @@
+package scraper
+
+import (
+	"github.com/ayanrajpoot10/hianime-api/internal/arcs"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// Episode classification sources
+const (
+	SourceDataset = "dataset"
+	SourceSite    = "site"
+)
+
+// EpisodeArcs returns the episodes of an anime grouped by story arc. Anime missing
+// from the arc dataset come back as a single unnamed group classified by the site.
+func (s *Scraper) EpisodeArcs(animeID string) (*models.ArcsResponse, error) {
+	episodes, err := s.Episodes(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ArcsResponse{
+		AnimeID:    animeID,
+		Source:     episodes.Source,
+		TotalItems: episodes.TotalItems,
+		Arcs:       arcs.Group(episodes.Episodes),
+	}, nil
+}
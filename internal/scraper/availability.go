@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"sync"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// EpisodeAvailability looks up the stream types and server names offered for an episode
+func (s *Scraper) EpisodeAvailability(episodeID string) (*models.EpisodeAvailability, error) {
+	if s.config.EnableCache {
+		if cached, ok := s.availability.Get(episodeID); ok {
+			return cached, nil
+		}
+	}
+
+	servers, err := s.Servers(episodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &models.EpisodeAvailability{
+		Types:   []string{},
+		Servers: map[string][]string{},
+	}
+	for _, serverType := range servers.Types {
+		availability.Types = append(availability.Types, serverType)
+		for _, server := range servers.List(serverType) {
+			availability.Servers[serverType] = append(availability.Servers[serverType], server.Name)
+		}
+	}
+
+	if s.config.EnableCache {
+		s.availability.Set(episodeID, availability)
+	}
+
+	return availability, nil
+}
+
+// annotateAvailability fills in the availability of each episode using a bounded pool
+// of workers. Episodes whose servers cannot be fetched carry the error instead.
+func (s *Scraper) annotateAvailability(episodes []models.EpisodeInfo) {
+	workers := max(s.config.AvailabilityWorkers, 1)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				availability, err := s.EpisodeAvailability(episodes[i].ID)
+				if err != nil {
+					availability = &models.EpisodeAvailability{
+						Types:   []string{},
+						Servers: map[string][]string{},
+						Error:   err.Error(),
+					}
+				}
+				episodes[i].Availability = availability
+			}
+		}()
+	}
+
+	for i := range episodes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
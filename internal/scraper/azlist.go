@@ -50,13 +50,11 @@ func (s *Scraper) GetAZList(sortOption string, page int) (*models.AZListResponse
 	}
 
 	// Construct the A-Z list URL
-	var azURL string
-	if urlSortOption == "" {
-		azURL = fmt.Sprintf("/az-list?page=%d", page)
-	} else {
-		azURL = fmt.Sprintf("/az-list/%s?page=%d", urlSortOption, page)
+	segments := []string{"az-list"}
+	if urlSortOption != "" {
+		segments = append(segments, urlSortOption)
 	}
-	url := s.config.BaseURL + azURL
+	url := s.buildURL(pageQuery(page), segments...)
 
 	if s.config.Verbose {
 		fmt.Printf("Making request to: %s\n", url)
@@ -77,7 +75,7 @@ func (s *Scraper) GetAZList(sortOption string, page int) (*models.AZListResponse
 
 	// Extract animes using the main content selector
 	selector := "#main-wrapper .tab-content .film_list-wrap .flw-item"
-	response.Animes = s.extractAnimes(doc, selector)
+	response.Animes = parseCards(doc.Selection, selector, filmCard)
 
 	// Extract pagination information
 	response.HasNextPage = s.extractHasNextPage(doc)
@@ -0,0 +1,272 @@
+package scraper
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/internal/ical"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// airingLength is the length given to calendar events, as the site publishes no end time
+const airingLength = 24 * time.Minute
+
+// genreLookupWorkers bounds the concurrent qtip lookups made to filter a calendar by genre
+const genreLookupWorkers = 8
+
+// genreLookupWait is how long a genre-filtered calendar waits for uncached genres
+const genreLookupWait = 10 * time.Second
+
+// ScheduleCalendar builds a calendar of the next seven days of airings. When anime
+// IDs or genres are given, only airings matching any of them are included.
+func (s *Scraper) ScheduleCalendar(animeIDs, genres []string) (*ical.Calendar, error) {
+	week, err := s.GetWeeklySchedule("", time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	var animes []models.ScheduledAnime
+	for _, day := range week.Days {
+		animes = append(animes, day.ScheduledAnimes...)
+	}
+
+	if len(animeIDs) > 0 || len(genres) > 0 {
+		if animes, err = s.filterAirings(animes, animeIDs, genres); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ical.Calendar{
+		Name:   "HiAnime airing schedule",
+		Events: s.airingEvents(animes),
+	}, nil
+}
+
+// AnimeCalendar builds a calendar holding the next airing of an anime. Bare numeric
+// IDs and links are resolved to the anime's slug first.
+func (s *Scraper) AnimeCalendar(animeID string) (*ical.Calendar, error) {
+	resolved, err := s.Resolve(animeID)
+	if err != nil {
+		return nil, err
+	}
+	animeID = resolved.AnimeID
+
+	next, err := s.GetNextEpisodeSchedule(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{Name: animeID, Events: []ical.Event{}}
+	if next.AiringTimestamp == nil {
+		return calendar, nil
+	}
+
+	airing, err := s.nextAiring(animeID, time.UnixMilli(*next.AiringTimestamp))
+	if err != nil {
+		return nil, err
+	}
+	if airing == nil {
+		return calendar, nil
+	}
+
+	if airing.Name != "" {
+		calendar.Name = airing.Name
+	}
+	calendar.Events = s.airingEvents([]models.ScheduledAnime{*airing})
+	return calendar, nil
+}
+
+// nextAiring finds the name and episode number of an anime's next airing in the
+// schedule of that day. It returns nil when the schedule doesn't list the airing,
+// as an event without the schedule's episode number would have no stable UID.
+func (s *Scraper) nextAiring(animeID string, at time.Time) (*models.ScheduledAnime, error) {
+	schedule, err := s.GetScheduleIn(at.UTC().Format(scheduleDateLayout), time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, anime := range schedule.ScheduledAnimes {
+		if anime.ID == animeID {
+			anime.AiringTimestamp = at.UnixMilli()
+			return &anime, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// filterAirings keeps the airings of the given anime IDs, slugs or numeric, and of
+// anime in any of the given genres
+func (s *Scraper) filterAirings(animes []models.ScheduledAnime, animeIDs, genres []string) ([]models.ScheduledAnime, error) {
+	wantedIDs := map[string]bool{}
+	for _, id := range animeIDs {
+		ref, err := parseAnimeRef(id)
+		if err != nil {
+			return nil, err
+		}
+		wantedIDs[ref.NumericID] = true
+	}
+
+	wantedGenres := map[string]bool{}
+	for _, genre := range genres {
+		wantedGenres[genreSlug(genre)] = true
+	}
+
+	var inGenre map[string]bool
+	if len(wantedGenres) > 0 {
+		inGenre = s.animesInGenres(animes, wantedGenres)
+	}
+
+	filtered := []models.ScheduledAnime{}
+	for _, anime := range animes {
+		id, _ := numericID(anime.ID)
+		if wantedIDs[id] || inGenre[anime.ID] {
+			filtered = append(filtered, anime)
+		}
+	}
+
+	return filtered, nil
+}
+
+// animesInGenres reports which scheduled anime are in any wanted genre. Genres come
+// from the cache; uncached anime are looked up by a background warm-up that the
+// request waits on for at most genreLookupWait. Anime still unknown after that are
+// left out, and later requests pick them up once the warm-up has cached them.
+func (s *Scraper) animesInGenres(animes []models.ScheduledAnime, wanted map[string]bool) map[string]bool {
+	var missing []string
+	seen := map[string]bool{}
+	for _, anime := range animes {
+		if seen[anime.ID] {
+			continue
+		}
+		seen[anime.ID] = true
+		if _, ok := s.qtipGenres.Get(anime.ID); !ok {
+			missing = append(missing, anime.ID)
+		}
+	}
+
+	if len(missing) > 0 {
+		select {
+		case <-s.genreWarmup(missing):
+		case <-time.After(genreLookupWait):
+		}
+	}
+
+	matches := map[string]bool{}
+	for id := range seen {
+		genres, _ := s.qtipGenres.Get(id)
+		for _, genre := range genres {
+			if wanted[genreSlug(genre)] {
+				matches[id] = true
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// genreWarmup returns a channel closed when the running genre warm-up finishes,
+// starting one for ids when none is running. Only one warm-up runs at a time, so
+// concurrent requests don't multiply the qtip lookups.
+func (s *Scraper) genreWarmup(ids []string) <-chan struct{} {
+	s.genreWarmMu.Lock()
+	defer s.genreWarmMu.Unlock()
+
+	if s.genreWarm != nil {
+		return s.genreWarm
+	}
+
+	done := make(chan struct{})
+	s.genreWarm = done
+	go func() {
+		s.cacheGenres(ids)
+
+		s.genreWarmMu.Lock()
+		s.genreWarm = nil
+		s.genreWarmMu.Unlock()
+		close(done)
+	}()
+
+	return done
+}
+
+// cacheGenres looks up the genres of each anime through qtip using a bounded pool of
+// workers. Anime whose genres cannot be fetched stay uncached.
+func (s *Scraper) cacheGenres(ids []string) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+
+	for w := 0; w < genreLookupWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				s.animeGenres(id)
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// animeGenres returns the genres of an anime, cached as they rarely change
+func (s *Scraper) animeGenres(animeID string) ([]string, error) {
+	if genres, ok := s.qtipGenres.Get(animeID); ok {
+		return genres, nil
+	}
+
+	qtip, err := s.GetAnimeQtipInfo(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.qtipGenres.Set(animeID, qtip.Anime.Genres)
+	return qtip.Anime.Genres, nil
+}
+
+// airingEvents turns scheduled airings into calendar events. The UID is built from
+// the numeric anime ID and the episode number, so a rescheduled episode replaces its
+// earlier event. Airings without an episode number are skipped, and only the first
+// airing of an episode is kept.
+func (s *Scraper) airingEvents(animes []models.ScheduledAnime) []ical.Event {
+	events := []ical.Event{}
+	seen := map[string]bool{}
+	for _, anime := range animes {
+		if anime.AiringTimestamp == 0 || anime.Episode <= 0 {
+			continue
+		}
+
+		id, err := numericID(anime.ID)
+		if err != nil {
+			continue
+		}
+
+		uid := fmt.Sprintf("anime-%s-ep%d@hianime-api", id, anime.Episode)
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+
+		start := time.UnixMilli(anime.AiringTimestamp).UTC()
+		name := anime.Name
+		if name == "" {
+			name = anime.ID
+		}
+
+		events = append(events, ical.Event{
+			UID:         uid,
+			Summary:     fmt.Sprintf("%s - Episode %d", name, anime.Episode),
+			Description: fmt.Sprintf("Episode %d of %s (estimated airing time)", anime.Episode, name),
+			URL:         s.watchURL(anime.ID, ""),
+			Start:       start,
+			End:         start.Add(airingLength),
+		})
+	}
+	return events
+}
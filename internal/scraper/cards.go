@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// cardLayout describes where the cards of one kind of list keep each field.
+// Selectors are relative to the card and empty selectors are skipped; when a
+// selector matches several elements the first one is used.
+type cardLayout struct {
+	Link     string // element whose href holds the anime ID, defaults to Title
+	Title    string // element holding the title and its data-jname
+	Poster   string // image with a data-src or src
+	Type     string
+	TypeTick string // tick block whose last word is the type, used when Type finds nothing
+	Duration string
+	Rating   string
+	Sub      string
+	Dub      string
+	Eps      string
+	Rank     string // element holding the rank number
+	Ranked   bool   // rank cards by position when Rank is empty
+
+	// Extra fills in fields only this kind of card has
+	Extra func(card *goquery.Selection, item *models.AnimeItem)
+}
+
+// Card layouts of the lists on the site
+var (
+	// filmCard is the poster grid used by listings, search and detail pages
+	filmCard = cardLayout{
+		Title:    ".film-detail .film-name .dynamic-name",
+		Poster:   ".film-poster .film-poster-img",
+		Type:     ".film-detail .fd-infor .fdi-item:nth-of-type(1)",
+		Duration: ".film-detail .fd-infor .fdi-item.fdi-duration",
+		Rating:   ".film-poster .tick-rate, .film-detail .fd-infor .fdi-item .imdb",
+		Sub:      ".film-poster .tick-sub",
+		Dub:      ".film-poster .tick-dub",
+	}
+
+	// relatedCard is the related anime list in the sidebar of detail pages, sidebar
+	// cards that may show how the anime is related
+	relatedCard = func() cardLayout {
+		layout := sidebarCard
+		layout.Extra = func(card *goquery.Selection, item *models.AnimeItem) {
+			item.Relation = relationType(card)
+		}
+		return layout
+	}()
+
+	// sidebarCard is the compact list used by the homepage featured blocks and sidebars
+	sidebarCard = cardLayout{
+		Title:    ".film-detail .dynamic-name, .film-detail .film-name a",
+		Poster:   ".film-poster img",
+		TypeTick: ".fd-infor .tick",
+		Sub:      ".tick-sub",
+		Dub:      ".tick-dub",
+	}
+
+	// top10Card is the ranked top 10 list
+	top10Card = cardLayout{
+		Title:  ".film-detail .dynamic-name",
+		Poster: ".film-poster .film-poster-img",
+		Sub:    ".film-detail .fd-infor .tick-item.tick-sub",
+		Dub:    ".film-detail .fd-infor .tick-item.tick-dub",
+		Rank:   ".film-number span",
+	}
+
+	// spotlightCard is the homepage spotlight carousel
+	spotlightCard = cardLayout{
+		Link:   ".desi-buttons a",
+		Title:  ".desi-head-title",
+		Poster: ".deslide-cover .film-poster-img",
+		Type:   ".sc-detail .scd-item",
+		Sub:    ".sc-detail .tick-sub",
+		Dub:    ".sc-detail .tick-dub",
+		Eps:    ".sc-detail .tick-eps",
+		Ranked: true,
+		Extra: func(card *goquery.Selection, item *models.AnimeItem) {
+			details := card.Find(".sc-detail")
+			item.Description = strings.TrimSpace(card.Find(".desi-description").Text())
+			item.Duration = strings.TrimSpace(details.Find(".scd-item").Eq(1).Text())
+			item.Aired = strings.TrimSpace(details.Find(".scd-item.m-hide").Text())
+			item.Quality = strings.TrimSpace(details.Find(".scd-item .quality").Text())
+			if item.Episodes.Eps == 0 {
+				item.Episodes.Eps = item.Episodes.Sub
+			}
+		},
+	}
+
+	// trendingCard is the homepage trending carousel
+	trendingCard = cardLayout{
+		Link:   ".film-poster",
+		Title:  ".item .film-title",
+		Poster: ".film-poster img",
+		Ranked: true,
+	}
+
+	// suggestionCard is a search suggestion entry
+	suggestionCard = cardLayout{
+		Link:   "a",
+		Title:  ".srp-detail .film-name",
+		Poster: ".film-poster img",
+		Type:   ".srp-detail .film-infor span",
+	}
+)
+
+// parseCards extracts an anime item from every card matching selector
+func parseCards(root *goquery.Selection, selector string, layout cardLayout) []models.AnimeItem {
+	var items []models.AnimeItem
+
+	root.Find(selector).Each(func(i int, card *goquery.Selection) {
+		items = append(items, parseCard(card, i, layout))
+	})
+
+	return items
+}
+
+// parseCard extracts an anime item from one card at position i of its list
+func parseCard(card *goquery.Selection, i int, layout cardLayout) models.AnimeItem {
+	item := models.AnimeItem{
+		Episodes: &models.Episodes{},
+	}
+
+	first := func(selector string) *goquery.Selection {
+		if selector == "" {
+			return &goquery.Selection{}
+		}
+		return card.Find(selector).First()
+	}
+	text := func(selector string) string {
+		return strings.TrimSpace(first(selector).Text())
+	}
+
+	title := first(layout.Title)
+	link := title
+	if layout.Link != "" {
+		link = first(layout.Link)
+	}
+
+	item.ID = pathID(link.AttrOr("href", ""))
+	item.Title = strings.TrimSpace(title.Text())
+	item.JName = strings.TrimSpace(title.AttrOr("data-jname", ""))
+	item.Poster = strings.TrimSpace(imageSource(first(layout.Poster)))
+	item.Duration = text(layout.Duration)
+	item.Rating = text(layout.Rating)
+
+	item.Type = text(layout.Type)
+	if item.Type == "" && layout.TypeTick != "" {
+		if words := strings.Fields(text(layout.TypeTick)); len(words) > 0 {
+			item.Type = words[len(words)-1]
+		}
+	}
+
+	item.Episodes.Sub = tickCount(text(layout.Sub))
+	item.Episodes.Dub = tickCount(text(layout.Dub))
+	item.Episodes.Eps = tickCount(text(layout.Eps))
+
+	if rank, err := strconv.Atoi(text(layout.Rank)); err == nil {
+		item.Rank = rank
+	} else if layout.Ranked {
+		item.Rank = i + 1
+	}
+
+	if layout.Extra != nil {
+		layout.Extra(card, &item)
+	}
+
+	return item
+}
+
+// tickCount parses an episode count badge such as "12" or "SUB 12"
+func tickCount(text string) int {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return 0
+	}
+	count, _ := strconv.Atoi(fields[len(fields)-1])
+	return count
+}
@@ -0,0 +1,272 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// catalogueTTL is how long the scraped genre catalogue is reused
+const catalogueTTL = 24 * time.Hour
+
+// ErrUnsupported is returned for categories and genres missing from the catalogue
+var ErrUnsupported = errors.New("unsupported")
+
+// categories lists the listing pages accepted by AnimeList with their display names
+var categories = []models.CatalogueEntry{
+	{Slug: "most-popular", Name: "Most Popular"},
+	{Slug: "top-airing", Name: "Top Airing"},
+	{Slug: "most-favorite", Name: "Most Favorite"},
+	{Slug: "completed", Name: "Completed"},
+	{Slug: "recently-added", Name: "Recently Added"},
+	{Slug: "recently-updated", Name: "Recently Updated"},
+	{Slug: "top-upcoming", Name: "Top Upcoming"},
+	{Slug: "subbed-anime", Name: "Subbed Anime"},
+	{Slug: "dubbed-anime", Name: "Dubbed Anime"},
+	{Slug: "movie", Name: "Movies"},
+	{Slug: "tv", Name: "TV Series"},
+	{Slug: "ova", Name: "OVAs"},
+	{Slug: "ona", Name: "ONAs"},
+	{Slug: "special", Name: "Specials"},
+	{Slug: "events", Name: "Events"},
+}
+
+// knownProducers seeds the producer catalogue, the site has no producer index page
+var knownProducers = []models.CatalogueEntry{
+	{Slug: "a-1-pictures", Name: "A-1 Pictures"},
+	{Slug: "bones", Name: "Bones"},
+	{Slug: "cloverworks", Name: "CloverWorks"},
+	{Slug: "david-production", Name: "David Production"},
+	{Slug: "j-c-staff", Name: "J.C.Staff"},
+	{Slug: "kyoto-animation", Name: "Kyoto Animation"},
+	{Slug: "lerche", Name: "Lerche"},
+	{Slug: "madhouse", Name: "Madhouse"},
+	{Slug: "mappa", Name: "MAPPA"},
+	{Slug: "pierrot", Name: "Pierrot"},
+	{Slug: "production-ig", Name: "Production I.G"},
+	{Slug: "shaft", Name: "Shaft"},
+	{Slug: "studio-ghibli", Name: "Studio Ghibli"},
+	{Slug: "sunrise", Name: "Sunrise"},
+	{Slug: "toei-animation", Name: "Toei Animation"},
+	{Slug: "trigger", Name: "Trigger"},
+	{Slug: "ufotable", Name: "ufotable"},
+	{Slug: "white-fox", Name: "White Fox"},
+	{Slug: "wit-studio", Name: "Wit Studio"},
+}
+
+// catalogue holds the scraped genres and the producers seen on detail pages
+type catalogue struct {
+	genresMu sync.Mutex
+	genres   []models.CatalogueEntry
+	fetched  time.Time
+
+	producersMu sync.Mutex
+	producers   map[string]models.CatalogueEntry
+}
+
+// Categories returns the listing categories accepted by AnimeList
+func (s *Scraper) Categories() *models.CatalogueResponse {
+	return s.catalogueResponse("", categories)
+}
+
+// Genres returns the genres linked from the homepage, cached for a day
+func (s *Scraper) Genres() (*models.CatalogueResponse, error) {
+	genres, err := s.genreCatalogue()
+	if err != nil {
+		return nil, err
+	}
+	return s.catalogueResponse("genre", genres), nil
+}
+
+// Producers returns well-known studios together with every producer seen on the
+// detail pages scraped so far
+func (s *Scraper) Producers() *models.CatalogueResponse {
+	s.catalogue.producersMu.Lock()
+	entries := make(map[string]models.CatalogueEntry, len(knownProducers)+len(s.catalogue.producers))
+	for _, entry := range knownProducers {
+		entries[entry.Slug] = entry
+	}
+	for slug, entry := range s.catalogue.producers {
+		entries[slug] = entry
+	}
+	s.catalogue.producersMu.Unlock()
+
+	producers := make([]models.CatalogueEntry, 0, len(entries))
+	for _, entry := range entries {
+		producers = append(producers, entry)
+	}
+	sort.Slice(producers, func(i, j int) bool {
+		return strings.ToLower(producers[i].Name) < strings.ToLower(producers[j].Name)
+	})
+
+	return s.catalogueResponse("producer", producers)
+}
+
+// ValidateCategory checks a category against the catalogue, suggesting the closest
+// match for typos
+func (s *Scraper) ValidateCategory(category string) error {
+	return validateSlug("category", category, categories)
+}
+
+// ValidateGenre checks a genre name or slug against the genre catalogue, suggesting the
+// closest match for typos. Genres are accepted unchecked when the catalogue is unavailable.
+func (s *Scraper) ValidateGenre(genre string) error {
+	genres, err := s.genreCatalogue()
+	if err != nil || len(genres) == 0 {
+		return nil
+	}
+	return validateSlug("genre", genreSlug(genre), genres)
+}
+
+// genreCatalogue returns the cached genres, scraping the homepage when they are stale.
+// The lock is only held to read and store the cache, so a slow homepage doesn't block
+// other lookups.
+func (s *Scraper) genreCatalogue() ([]models.CatalogueEntry, error) {
+	s.catalogue.genresMu.Lock()
+	genres, fetched := s.catalogue.genres, s.catalogue.fetched
+	s.catalogue.genresMu.Unlock()
+
+	if genres != nil && time.Since(fetched) < catalogueTTL {
+		return genres, nil
+	}
+
+	resp, err := s.client.Get(s.buildURL(nil, "home"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	genres = []models.CatalogueEntry{}
+	doc.Find(".genre-list a, .footer_menu a[href*='genre']").Each(func(i int, sel *goquery.Selection) {
+		href := sel.AttrOr("href", "")
+		name := strings.TrimSpace(sel.Text())
+		if !strings.Contains(href, "/genre/") || name == "" {
+			return
+		}
+		entry := models.CatalogueEntry{Slug: pathID(href), Name: name}
+		for _, existing := range genres {
+			if existing.Slug == entry.Slug {
+				return
+			}
+		}
+		genres = append(genres, entry)
+	})
+
+	if len(genres) == 0 {
+		return nil, fmt.Errorf("no genres found on homepage")
+	}
+
+	s.catalogue.genresMu.Lock()
+	s.catalogue.genres = genres
+	s.catalogue.fetched = time.Now()
+	s.catalogue.genresMu.Unlock()
+	return genres, nil
+}
+
+// recordProducers adds the producer and studio links of a detail page to the catalogue
+func (s *Scraper) recordProducers(sel *goquery.Selection) {
+	s.catalogue.producersMu.Lock()
+	defer s.catalogue.producersMu.Unlock()
+
+	sel.Find("a[href*='/producer/']").Each(func(i int, a *goquery.Selection) {
+		slug := pathID(a.AttrOr("href", ""))
+		name := strings.TrimSpace(a.Text())
+		if slug == "" || name == "" {
+			return
+		}
+		if s.catalogue.producers == nil {
+			s.catalogue.producers = make(map[string]models.CatalogueEntry)
+		}
+		s.catalogue.producers[slug] = models.CatalogueEntry{Slug: slug, Name: name}
+	})
+}
+
+// catalogueResponse fills in the links of catalogue entries, which live under path
+// or at the site root when path is empty
+func (s *Scraper) catalogueResponse(path string, entries []models.CatalogueEntry) *models.CatalogueResponse {
+	response := &models.CatalogueResponse{
+		Items: make([]models.CatalogueEntry, len(entries)),
+		Total: len(entries),
+	}
+	for i, entry := range entries {
+		if path == "" {
+			entry.URL = s.buildURL(nil, entry.Slug)
+		} else {
+			entry.URL = s.buildURL(nil, path, entry.Slug)
+		}
+		response.Items[i] = entry
+	}
+	return response
+}
+
+// genreSlug turns a genre name such as "Slice of Life" into its slug
+func genreSlug(genre string) string {
+	return strings.Join(strings.Fields(strings.ToLower(genre)), "-")
+}
+
+// validateSlug reports an error naming the closest entry when slug is not in entries
+func validateSlug(kind, slug string, entries []models.CatalogueEntry) error {
+	slugs := make([]string, len(entries))
+	for i, entry := range entries {
+		if strings.EqualFold(entry.Slug, slug) {
+			return nil
+		}
+		slugs[i] = entry.Slug
+	}
+
+	if match := closestMatch(strings.ToLower(slug), slugs); match != "" {
+		return fmt.Errorf("%w %s: %s (did you mean %s?)", ErrUnsupported, kind, slug, match)
+	}
+	return fmt.Errorf("%w %s: %s", ErrUnsupported, kind, slug)
+}
+
+// closestMatch returns the candidate with the smallest edit distance to input, or ""
+// when none is within a third of the input's length
+func closestMatch(input string, candidates []string) string {
+	best, bestDistance := "", len(input)/3+1
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, input) && len(input) >= 3 {
+			return candidate
+		}
+		if d := editDistance(input, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
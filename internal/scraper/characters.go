@@ -0,0 +1,187 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxCharacterPages bounds the "view more" pagination in case the page count is missing
+const maxCharacterPages = 50
+
+var pageNumberRegex = regexp.MustCompile(`page=(\d+)`)
+
+// Characters scrapes every character and voice actor of an anime, following the
+// paginated character list behind the detail page's "view more" link
+func (s *Scraper) Characters(animeID string) (*models.CharactersResponse, error) {
+	animeID = strings.TrimSpace(animeID)
+	id, err := numericID(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.CharactersResponse{
+		AnimeID:    animeID,
+		Characters: []models.Character{},
+	}
+	seen := make(map[string]bool)
+
+	for page, lastPage := 1, maxCharacterPages; page <= lastPage; page++ {
+		doc, err := s.characterListPage(animeID, id, page)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			break
+		}
+
+		if last := lastPageNumber(doc); last > 0 && last < lastPage {
+			lastPage = last
+		}
+
+		added := 0
+		for _, character := range parseCharacters(doc.Selection) {
+			key := character.ID + "|" + character.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			response.Characters = append(response.Characters, character)
+			added++
+		}
+
+		if added == 0 {
+			break
+		}
+	}
+
+	response.Total = len(response.Characters)
+	return response, nil
+}
+
+// characterListPage fetches one page of the AJAX character list
+func (s *Scraper) characterListPage(animeID, id string, page int) (*goquery.Document, error) {
+	url := s.buildURL(pageQuery(page), "ajax", "character", "list", id)
+
+	resp, err := s.client.GetWithHeaders(url, map[string]string{
+		"Referer":          s.buildURL(nil, "character", "list", animeID),
+		"X-Requested-With": "XMLHttpRequest",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var ajaxResp struct {
+		HTML string `json:"html"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ajaxResp); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(ajaxResp.HTML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return doc, nil
+}
+
+// lastPageNumber returns the highest page linked from the pagination block, or 0 if there is none
+func lastPageNumber(doc *goquery.Document) int {
+	last := 0
+	doc.Find(".pagination a").Each(func(i int, sel *goquery.Selection) {
+		candidates := []string{sel.AttrOr("data-page", "")}
+		if match := pageNumberRegex.FindStringSubmatch(sel.AttrOr("href", "")); match != nil {
+			candidates = append(candidates, match[1])
+		}
+		for _, candidate := range candidates {
+			if n, err := strconv.Atoi(candidate); err == nil && n > last {
+				last = n
+			}
+		}
+	})
+	return last
+}
+
+// parseCharacters extracts characters and their voice actors from .bac-item blocks
+func parseCharacters(root *goquery.Selection) []models.Character {
+	characters := []models.Character{}
+
+	root.Find(".bac-list-wrap .bac-item").Each(func(i int, item *goquery.Selection) {
+		charInfo := item.Find(".per-info.ltr")
+		character := models.Character{
+			ID:      pathID(charInfo.Find(".pi-avatar").AttrOr("href", "")),
+			Name:    strings.TrimSpace(charInfo.Find(".pi-name").Text()),
+			Picture: imageSource(charInfo.Find(".pi-avatar img")),
+			Role:    strings.TrimSpace(charInfo.Find(".pi-cast").Text()),
+		}
+		if character.Name == "" {
+			return
+		}
+
+		// The primary voice actor is shown in full, others only as avatars
+		item.Find(".per-info.rtl").Each(func(j int, actorInfo *goquery.Selection) {
+			actor := models.VoiceActor{
+				ID:       pathID(actorInfo.Find(".pi-avatar").First().AttrOr("href", "")),
+				Name:     strings.TrimSpace(actorInfo.Find(".pi-name").First().Text()),
+				Picture:  imageSource(actorInfo.Find(".pi-avatar img").First()),
+				Language: strings.TrimSpace(actorInfo.Find(".pi-cast").First().Text()),
+			}
+			if actor.Name != "" {
+				character.VoiceActors = append(character.VoiceActors, actor)
+			}
+		})
+
+		item.Find(".per-info-xx .pixx-item").Each(func(j int, extra *goquery.Selection) {
+			avatar := extra.Find("a").First()
+			actor := models.VoiceActor{
+				ID:      pathID(avatar.AttrOr("href", "")),
+				Name:    strings.TrimSpace(avatar.AttrOr("title", "")),
+				Picture: imageSource(extra.Find("img")),
+			}
+			if actor.Name == "" {
+				actor.Name = strings.TrimSpace(extra.Find("img").AttrOr("alt", ""))
+			}
+			if actor.Name != "" {
+				character.VoiceActors = append(character.VoiceActors, actor)
+			}
+		})
+
+		characters = append(characters, character)
+	})
+
+	return characters
+}
+
+// pathID returns the last path segment of a link such as "/character/monkey-d-luffy-1"
+func pathID(href string) string {
+	href = strings.TrimSpace(href)
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		href = href[:i]
+	}
+	href = strings.TrimRight(href, "/")
+	if i := strings.LastIndex(href, "/"); i >= 0 {
+		return href[i+1:]
+	}
+	return href
+}
+
+// imageSource returns an image's lazy-loaded source, falling back to src
+func imageSource(img *goquery.Selection) string {
+	if src := img.AttrOr("data-src", ""); src != "" {
+		return src
+	}
+	return img.AttrOr("src", "")
+}
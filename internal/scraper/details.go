@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -13,7 +14,7 @@ import (
 
 // AnimeDetails scrapes detailed information about a specific anime
 func (s *Scraper) AnimeDetails(animeID string) (*models.AnimeDetailResponse, error) {
-	url := fmt.Sprintf("%s/%s", s.config.BaseURL, animeID)
+	url := s.buildURL(nil, animeID)
 
 	resp, err := s.client.Get(url)
 	if err != nil {
@@ -52,8 +53,25 @@ func (s *Scraper) AnimeDetails(animeID string) (*models.AnimeDetailResponse, err
 	detail.Poster = doc.Find(".anisc-poster .film-poster-img").AttrOr("src", "")
 	detail.Description = strings.TrimSpace(doc.Find(".film-description.m-hide .text").Text())
 	detail.Episodes = &models.Episodes{}
-	detail.RelatedAnimes = s.extractAnimes(doc, ".block_area .tab-content .flw-item")
-	detail.RecommendedAnimes = s.extractAnimes(doc, ".block_area:contains('You might also like') .flw-item")
+
+	// Related anime live in the sidebar, recommendations in the main column
+	relatedSelector := "#main-sidebar .block_area:contains('Related Anime') .anif-block-ul li"
+	detail.RelatedAnimes = parseCards(doc.Selection, relatedSelector, relatedCard)
+	detail.RelatedAnimes = dedupeAnimes(detail.RelatedAnimes, map[string]bool{animeID: true})
+
+	recommendedSelector := "#main-content .block_area:contains('Recommended for you') .flw-item, " +
+		"#main-content .block_area:contains('You might also like') .flw-item"
+	exclude := map[string]bool{animeID: true}
+	for _, item := range detail.RelatedAnimes {
+		exclude[item.ID] = true
+	}
+	detail.RecommendedAnimes = dedupeAnimes(parseCards(doc.Selection, recommendedSelector, filmCard), exclude)
+
+	// Extract characters and voice actors shown on the detail page, the full
+	// list is available through Characters
+	if characters := parseCharacters(doc.Find(".block_area-actors")); len(characters) > 0 {
+		detail.Characters = characters
+	}
 
 	// Extract Japanese title and synonyms
 	doc.Find(".anisc-info .item").Each(func(i int, sel *goquery.Selection) {
@@ -81,8 +99,22 @@ func (s *Scraper) AnimeDetails(animeID string) (*models.AnimeDetailResponse, err
 			detail.PremiereDate = value
 		case "studios:":
 			detail.Studios = extractList(sel)
+			s.recordProducers(sel)
 		case "producers:":
 			detail.Producers = extractList(sel)
+			s.recordProducers(sel)
+		case "licensors:":
+			detail.Licensors = extractList(sel)
+		case "japanese:":
+			detail.Japanese = value
+		case "synonyms:":
+			// The page lists synonyms as one comma-joined text, so splitting on ", " is
+			// best-effort: titles with a bare comma stay whole, titles with ", " don't
+			for _, synonym := range strings.Split(value, ", ") {
+				if synonym = strings.TrimSpace(synonym); synonym != "" {
+					detail.Synonyms = append(detail.Synonyms, synonym)
+				}
+			}
 		case "genres:":
 			detail.Genres = extractList(sel)
 		}
@@ -99,6 +131,31 @@ func (s *Scraper) AnimeDetails(animeID string) (*models.AnimeDetailResponse, err
 		detail.Episodes.Eps = epsCount
 	}
 
+	// Extract MAL and AniList IDs from the embedded sync data
+	// The IDs are strings on most pages but plain numbers on some
+	var syncData struct {
+		MALID     any `json:"mal_id"`
+		AniListID any `json:"anilist_id"`
+	}
+	if raw := doc.Find("#syncData").Text(); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &syncData); err == nil {
+			detail.MALID = syncID(syncData.MALID)
+			detail.AniListID = syncID(syncData.AniListID)
+		}
+	}
+
+	// Extract trailers and promotional videos
+	doc.Find(".block_area-promotions .screen-items .item").Each(func(i int, sel *goquery.Selection) {
+		trailer := models.Trailer{
+			Title:     strings.TrimSpace(sel.AttrOr("data-title", "")),
+			URL:       strings.TrimSpace(sel.AttrOr("data-src", "")),
+			Thumbnail: imageSource(sel.Find("img")),
+		}
+		if trailer.URL != "" {
+			detail.Trailers = append(detail.Trailers, trailer)
+		}
+	})
+
 	// Extract other seasons
 	detail.OtherSeasons = []models.Season{}
 	doc.Find(".block_area-seasons .os-list .os-item").Each(func(i int, sel *goquery.Selection) {
@@ -116,3 +173,55 @@ func (s *Scraper) AnimeDetails(animeID string) (*models.AnimeDetailResponse, err
 
 	return detail, nil
 }
+
+// syncID converts an ID from the sync data, given as a string or a JSON number, to an int
+func syncID(value any) int {
+	switch v := value.(type) {
+	case string:
+		id, _ := strconv.Atoi(strings.TrimSpace(v))
+		return id
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// relationKinds lists relation labels the site may show on related anime
+var relationKinds = []string{
+	"sequel", "prequel", "side story", "spin-off", "alternative version", "alternative setting",
+	"parent story", "full story", "summary", "character", "other",
+}
+
+// relationType returns the relation of a related anime when the page exposes it
+func relationType(sel *goquery.Selection) string {
+	if relation := strings.TrimSpace(sel.AttrOr("data-relation", "")); relation != "" {
+		return strings.ToLower(relation)
+	}
+
+	relation := ""
+	sel.Find(".relation, .fd-infor .fdi-item, .fd-infor span").EachWithBreak(func(i int, info *goquery.Selection) bool {
+		text := strings.ToLower(strings.TrimSpace(info.Text()))
+		for _, kind := range relationKinds {
+			if text == kind {
+				relation = kind
+				return false
+			}
+		}
+		return true
+	})
+	return relation
+}
+
+// dedupeAnimes drops items without an ID, repeated items and items whose ID is in exclude
+func dedupeAnimes(items []models.AnimeItem, exclude map[string]bool) []models.AnimeItem {
+	seen := make(map[string]bool)
+	result := []models.AnimeItem{}
+	for _, item := range items {
+		if item.ID == "" || seen[item.ID] || exclude[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		result = append(result, item)
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package scraper
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,16 +15,15 @@ import (
 
 // Episodes scrapes episode list for a specific anime
 func (s *Scraper) Episodes(animeID string) (*models.EpisodesResponse, error) {
-	parts := strings.Split(animeID, "-")
-	if len(parts) == 0 {
-		return nil, fmt.Errorf("invalid anime ID format")
+	id, err := numericID(animeID)
+	if err != nil {
+		return nil, err
 	}
-	numericID := parts[len(parts)-1]
 
-	url := fmt.Sprintf("%s/ajax/v2/episode/list/%s", s.config.BaseURL, numericID)
+	url := s.buildURL(nil, "ajax", "v2", "episode", "list", id)
 
 	headers := map[string]string{
-		"Referer":          fmt.Sprintf("%s/watch/%s", s.config.BaseURL, animeID),
+		"Referer":          s.watchURL(animeID, ""),
 		"X-Requested-With": "XMLHttpRequest",
 	}
 
@@ -69,7 +69,7 @@ func (s *Scraper) Episodes(animeID string) (*models.EpisodesResponse, error) {
 
 	response := &models.EpisodesResponse{
 		TotalItems: ajaxResp.TotalItems,
-}
+	}
 
 	doc.Find(".ss-list a.ssl-item.ep-item").Each(func(i int, sel *goquery.Selection) {
 		episode := models.EpisodeInfo{}
@@ -99,5 +99,125 @@ func (s *Scraper) Episodes(animeID string) (*models.EpisodesResponse, error) {
 		response.Episodes = append(response.Episodes, episode)
 	})
 
+	// Refine the site's filler flag with arc data when available
+	response.Source = SourceSite
+	if s.arcDataset().Classify(animeID, response.Episodes) {
+		response.Source = SourceDataset
+	}
+
 	return response, nil
 }
+
+// Filler filter modes
+const (
+	FillersInclude = "include"
+	FillersExclude = "exclude"
+	FillersOnly    = "only"
+)
+
+// MaxAvailabilityLimit is the largest page that may be annotated with availability
+const MaxAvailabilityLimit = 100
+
+// EpisodeFilter selects a page of an episode list. Zero values disable a filter.
+// Availability annotates the returned episodes with their sub/dub servers, costing
+// one request per uncached episode, so it needs a Limit of at most MaxAvailabilityLimit.
+type EpisodeFilter struct {
+	Offset       int
+	Limit        int
+	From         int
+	To           int
+	Fillers      string
+	Availability bool
+}
+
+// Validate checks the filter values
+func (f EpisodeFilter) Validate() error {
+	if f.Offset < 0 || f.Limit < 0 || f.From < 0 || f.To < 0 {
+		return fmt.Errorf("offset, limit, from and to must not be negative")
+	}
+	if f.To > 0 && f.From > f.To {
+		return fmt.Errorf("invalid episode range: %d-%d", f.From, f.To)
+	}
+	if f.Availability && (f.Limit == 0 || f.Limit > MaxAvailabilityLimit) {
+		return fmt.Errorf("availability requires a limit between 1 and %d", MaxAvailabilityLimit)
+	}
+	switch strings.ToLower(f.Fillers) {
+	case "", FillersInclude, FillersExclude, FillersOnly:
+		return nil
+	}
+	return fmt.Errorf("invalid fillers filter: %s (use include, exclude or only)", f.Fillers)
+}
+
+// FilteredEpisodes returns the episodes of an anime matching the filter. Range and
+// filler filters apply first, then offset and limit page through the matches.
+func (s *Scraper) FilteredEpisodes(animeID string, filter EpisodeFilter) (*models.EpisodesResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	all, err := s.Episodes(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := []models.EpisodeInfo{}
+	for _, ep := range all.Episodes {
+		if filter.From > 0 && ep.Episode < filter.From {
+			continue
+		}
+		if filter.To > 0 && ep.Episode > filter.To {
+			continue
+		}
+		switch strings.ToLower(filter.Fillers) {
+		case FillersExclude:
+			if ep.IsFiller {
+				continue
+			}
+		case FillersOnly:
+			if !ep.IsFiller {
+				continue
+			}
+		}
+		matched = append(matched, ep)
+	}
+
+	start := min(filter.Offset, len(matched))
+	end := len(matched)
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, len(matched))
+	}
+
+	page := matched[start:end]
+	if filter.Availability {
+		s.annotateAvailability(page)
+	}
+
+	return &models.EpisodesResponse{
+		Episodes:   page,
+		TotalItems: all.TotalItems,
+		Matched:    len(matched),
+		Offset:     filter.Offset,
+		Limit:      filter.Limit,
+		HasMore:    end < len(matched),
+		Source:     all.Source,
+	}, nil
+}
+
+// ErrEpisodeNotFound is returned when an anime has no episode with the requested number
+var ErrEpisodeNotFound = errors.New("episode not found")
+
+// Episode looks up a single episode of an anime by its number
+func (s *Scraper) Episode(animeID string, number int) (*models.EpisodeInfo, error) {
+	episodes, err := s.Episodes(animeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ep := range episodes.Episodes {
+		if ep.Episode == number {
+			return &ep, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s #%d", ErrEpisodeNotFound, animeID, number)
+}
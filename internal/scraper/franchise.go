@@ -0,0 +1,121 @@
+package scraper
+
+import (
+	"fmt"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// Franchise graph limits, each expanded node costs one detail page request
+const (
+	maxFranchiseDepth = 3
+	maxFranchiseNodes = 30
+)
+
+// Franchise builds a graph of the seasons and related anime reachable from animeID.
+// Nodes up to depth hops away are expanded by fetching their detail pages.
+func (s *Scraper) Franchise(animeID string, depth int) (*models.FranchiseResponse, error) {
+	if depth < 1 {
+		depth = 1
+	}
+	if depth > maxFranchiseDepth {
+		depth = maxFranchiseDepth
+	}
+
+	response := &models.FranchiseResponse{
+		Root:  animeID,
+		Nodes: []models.FranchiseNode{},
+		Edges: []models.FranchiseEdge{},
+	}
+
+	nodes := make(map[string]int)
+	edges := make(map[string]bool)
+
+	addNode := func(node models.FranchiseNode) {
+		if i, ok := nodes[node.ID]; ok {
+			// Fill in details learnt from the node's own page
+			existing := &response.Nodes[i]
+			if node.Title != "" {
+				existing.Title = node.Title
+			}
+			if node.Poster != "" {
+				existing.Poster = node.Poster
+			}
+			if node.Type != "" {
+				existing.Type = node.Type
+			}
+			return
+		}
+		nodes[node.ID] = len(response.Nodes)
+		response.Nodes = append(response.Nodes, node)
+	}
+
+	addEdge := func(from, to, relation string) {
+		key := from + "|" + to
+		reverse := to + "|" + from
+		if from == to || edges[key] || (relation == "season" && edges[reverse]) {
+			return
+		}
+		edges[key] = true
+		response.Edges = append(response.Edges, models.FranchiseEdge{From: from, To: to, Relation: relation})
+	}
+
+	type queued struct {
+		id    string
+		depth int
+	}
+	queue := []queued{{animeID, 0}}
+	expanded := make(map[string]bool)
+
+	for len(queue) > 0 && len(expanded) < maxFranchiseNodes {
+		current := queue[0]
+		queue = queue[1:]
+		if expanded[current.id] {
+			continue
+		}
+		expanded[current.id] = true
+
+		details, err := s.AnimeDetails(current.id)
+		if err != nil {
+			if current.id == animeID {
+				return nil, fmt.Errorf("failed to get anime details: %w", err)
+			}
+			continue
+		}
+
+		addNode(models.FranchiseNode{
+			ID:     current.id,
+			Title:  details.Title,
+			Poster: details.Poster,
+			Type:   details.Type,
+			Depth:  current.depth,
+		})
+
+		next := current.depth + 1
+
+		for _, season := range details.OtherSeasons {
+			if season.ID == "" {
+				continue
+			}
+			addNode(models.FranchiseNode{ID: season.ID, Title: season.Title, Poster: season.Poster, Depth: next})
+			addEdge(current.id, season.ID, "season")
+			if next < depth {
+				queue = append(queue, queued{season.ID, next})
+			}
+		}
+
+		for _, related := range details.RelatedAnimes {
+			relation := related.Relation
+			if relation == "" {
+				relation = "related"
+			}
+			addNode(models.FranchiseNode{ID: related.ID, Title: related.Title, Poster: related.Poster, Type: related.Type, Depth: next})
+			addEdge(current.id, related.ID, relation)
+			if next < depth {
+				queue = append(queue, queued{related.ID, next})
+			}
+		}
+	}
+
+	return response, nil
+}
@@ -3,7 +3,6 @@ package scraper
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/ayanrajpoot10/hianime-api/pkg/models"
@@ -13,7 +12,7 @@ import (
 
 // Homepage scrapes the homepage content including spotlight, trending, etc.
 func (s *Scraper) Homepage() (*models.HomepageResponse, error) {
-	resp, err := s.client.Get(s.config.BaseURL + "/home")
+	resp, err := s.client.Get(s.buildURL(nil, "home"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -68,137 +67,61 @@ func (s *Scraper) Homepage() (*models.HomepageResponse, error) {
 
 // extractSpotlight extracts spotlight anime from the homepage
 func (s *Scraper) extractSpotlight(doc *goquery.Document) []models.AnimeItem {
-	var items []models.AnimeItem
-
-	doc.Find(".deslide-wrap .swiper-wrapper .swiper-slide").Each(func(i int, sel *goquery.Selection) {
-		item := models.AnimeItem{}
-		item.Rank = i + 1
-
-		// Extract ID from href
-		href, exists := sel.Find(".desi-buttons a").First().Attr("href")
-		if exists {
-			parts := strings.Split(href, "/")
-			if len(parts) > 0 {
-				item.ID = parts[len(parts)-1]
-			}
-		}
-
-		// Extract poster
-		item.Poster, _ = sel.Find(".deslide-cover .film-poster-img").Attr("data-src")
-
-		// Extract title and jname
-		item.Title = strings.TrimSpace(sel.Find(".desi-head-title").Text())
-		item.JName, _ = sel.Find(".desi-head-title").Attr("data-jname")
-
-		// Extract description
-		item.Description = strings.TrimSpace(sel.Find(".desi-description").Text())
-
-		// Extract details
-		details := sel.Find(".sc-detail")
-		item.Type = strings.TrimSpace(details.Find(".scd-item").Eq(0).Text())
-		item.Duration = strings.TrimSpace(details.Find(".scd-item").Eq(1).Text())
-		item.Aired = strings.TrimSpace(details.Find(".scd-item.m-hide").Text())
-		item.Quality = strings.TrimSpace(details.Find(".scd-item .quality").Text())
-
-		// Initialize Episodes to avoid nil pointer dereference
-		item.Episodes = &models.Episodes{}
-
-		// Extract episode information
-		subText := strings.TrimSpace(details.Find(".tick-sub").Text())
-		dubText := strings.TrimSpace(details.Find(".tick-dub").Text())
-		epsText := strings.TrimSpace(details.Find(".tick-eps").Text())
-		if epsText == "" {
-			epsText = subText
-		}
-
-		item.Episodes.Sub, _ = strconv.Atoi(subText)
-		item.Episodes.Dub, _ = strconv.Atoi(dubText)
-		item.Episodes.Eps, _ = strconv.Atoi(epsText)
-
-		items = append(items, item)
-	})
-
-	return items
+	return parseCards(doc.Selection, ".deslide-wrap .swiper-wrapper .swiper-slide", spotlightCard)
 }
 
 // extractTrending extracts trending anime from the homepage
 func (s *Scraper) extractTrending(doc *goquery.Document) []models.AnimeItem {
-	var items []models.AnimeItem
-
-	doc.Find("#trending-home .swiper-container .swiper-slide").Each(func(i int, sel *goquery.Selection) {
-		item := models.AnimeItem{}
-		item.Rank = i + 1
-
-		// Extract title and jname
-		titleEl := sel.Find(".item .film-title")
-		item.Title = strings.TrimSpace(titleEl.Text())
-		item.JName, _ = titleEl.Attr("data-jname")
-
-		// Extract poster and ID
-		imageEl := sel.Find(".film-poster")
-		item.Poster, _ = imageEl.Find("img").Attr("data-src")
-
-		href, exists := imageEl.Attr("href")
-		if exists {
-			parts := strings.Split(href, "/")
-			if len(parts) > 0 {
-				item.ID = parts[len(parts)-1]
-			}
-		}
-
-		items = append(items, item)
-	})
-
-	return items
+	return parseCards(doc.Selection, "#trending-home .swiper-container .swiper-slide", trendingCard)
 }
 
 // extractLatestCompleted extracts latest completed anime
 func (s *Scraper) extractLatestCompleted(doc *goquery.Document) []models.AnimeItem {
-	return s.extractMostPopularAnimes(doc, "#anime-featured .row div:nth-of-type(4) .anif-block-ul ul li")
+	return parseCards(doc.Selection, "#anime-featured .row div:nth-of-type(4) .anif-block-ul ul li", sidebarCard)
 }
 
 // extractTopAiring extracts top airing anime
 func (s *Scraper) extractTopAiring(doc *goquery.Document) []models.AnimeItem {
-	return s.extractMostPopularAnimes(doc, "#anime-featured .row div:nth-of-type(1) .anif-block-ul ul li")
+	return parseCards(doc.Selection, "#anime-featured .row div:nth-of-type(1) .anif-block-ul ul li", sidebarCard)
 }
 
 // extractMostPopular extracts most popular anime
 func (s *Scraper) extractMostPopular(doc *goquery.Document) []models.AnimeItem {
-	return s.extractMostPopularAnimes(doc, "#anime-featured .row div:nth-of-type(2) .anif-block-ul ul li")
+	return parseCards(doc.Selection, "#anime-featured .row div:nth-of-type(2) .anif-block-ul ul li", sidebarCard)
 }
 
 // extractMostFavorite extracts most favorite anime
 func (s *Scraper) extractMostFavorite(doc *goquery.Document) []models.AnimeItem {
-	return s.extractMostPopularAnimes(doc, "#anime-featured .row div:nth-of-type(3) .anif-block-ul ul li")
+	return parseCards(doc.Selection, "#anime-featured .row div:nth-of-type(3) .anif-block-ul ul li", sidebarCard)
 }
 
 // extractRecentlyAdded extracts recently added anime
 func (s *Scraper) extractRecentlyAdded(doc *goquery.Document) []models.AnimeItem {
-	return s.extractAnimes(doc, "#main-content .block_area_home:contains('Recently Added') .film_list .film_list-wrap .flw-item")
+	return parseCards(doc.Selection, "#main-content .block_area_home:contains('Recently Added') .film_list .film_list-wrap .flw-item", filmCard)
 }
 
 // extractLatestUpdated extracts latest updated anime
 func (s *Scraper) extractLatestUpdated(doc *goquery.Document) []models.AnimeItem {
-	return s.extractAnimes(doc, "#main-content .block_area_home:nth-of-type(1) .tab-content .film_list-wrap .flw-item")
+	return parseCards(doc.Selection, "#main-content .block_area_home:nth-of-type(1) .tab-content .film_list-wrap .flw-item", filmCard)
 }
 
 // extractTopUpcoming extracts top upcoming anime
 func (s *Scraper) extractTopUpcoming(doc *goquery.Document) []models.AnimeItem {
-	return s.extractAnimes(doc, "#main-content .block_area_home:nth-of-type(3) .tab-content .film_list-wrap .flw-item")
+	return parseCards(doc.Selection, "#main-content .block_area_home:nth-of-type(3) .tab-content .film_list-wrap .flw-item", filmCard)
 }
 
-// extractTop10 extracts top 10 rankings
+// extractTop10 extracts top 10 rankings, shared by the homepage and producer pages
 func (s *Scraper) extractTop10(doc *goquery.Document) models.Top10 {
 	top10 := models.Top10{}
 
 	// Extract Today's top 10 (day period)
-	top10.Today = s.extractTop10Animes(doc, "day")
+	top10.Today = parseCards(doc.Selection, "#top-viewed-day ul li", top10Card)
 
 	// Extract Week's top 10
-	top10.Week = s.extractTop10Animes(doc, "week")
+	top10.Week = parseCards(doc.Selection, "#top-viewed-week ul li", top10Card)
 
 	// Extract Month's top 10
-	top10.Month = s.extractTop10Animes(doc, "month")
+	top10.Month = parseCards(doc.Selection, "#top-viewed-month ul li", top10Card)
 
 	return top10
 }
@@ -0,0 +1,179 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// slugTTL is how long a resolved numeric ID to slug mapping is reused
+const slugTTL = 24 * time.Hour
+
+// ErrAnimeNotFound is returned when the site has no anime with the requested ID
+var ErrAnimeNotFound = errors.New("anime not found")
+
+// ErrInvalidID is returned for anime IDs that are neither a slug nor a numeric ID
+var ErrInvalidID = errors.New("invalid anime id")
+
+var (
+	slugRegex    = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*-([0-9]+)$`)
+	numericRegex = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// animeRef is an anime, and optionally one of its episodes, parsed from user input
+type animeRef struct {
+	Slug      string // empty when only the numeric ID is known
+	NumericID string
+	Episode   string
+}
+
+// numericID returns the numeric ID of a slug such as "one-piece-100", or of a bare
+// numeric ID. Anything else is rejected rather than guessed at.
+func numericID(animeID string) (string, error) {
+	animeID = strings.TrimSpace(animeID)
+	if numericRegex.MatchString(animeID) {
+		return animeID, nil
+	}
+	if m := slugRegex.FindStringSubmatch(animeID); m != nil {
+		return m[1], nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidID, animeID)
+}
+
+// parseAnimeRef parses a slug, a bare numeric ID, an episode ID ("slug::ep=N") or a
+// hianime link such as "https://hianime.to/watch/one-piece-100?ep=2142"
+func parseAnimeRef(input string) (*animeRef, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf("empty anime reference")
+	}
+
+	id, episode := input, ""
+	switch {
+	case strings.Contains(input, "::ep="):
+		id, episode, _ = strings.Cut(input, "::ep=")
+	case strings.Contains(input, "/"):
+		var err error
+		if id, episode, err = parseAnimeLink(input); err != nil {
+			return nil, err
+		}
+	}
+
+	if episode != "" && !numericRegex.MatchString(episode) {
+		return nil, fmt.Errorf("invalid episode id: %s", episode)
+	}
+
+	numeric, err := numericID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := &animeRef{NumericID: numeric, Episode: episode}
+	if numeric != id {
+		ref.Slug = id
+	}
+	return ref, nil
+}
+
+// parseAnimeLink extracts the anime ID and "ep" query parameter of a detail or
+// watch page link. The host is not checked so mirror domains work too.
+func parseAnimeLink(link string) (string, string, error) {
+	if !strings.Contains(link, "://") && !strings.HasPrefix(link, "/") {
+		link = "https://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse link: %w", err)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] != "":
+		return segments[0], u.Query().Get("ep"), nil
+	case len(segments) == 2 && segments[0] == "watch":
+		return segments[1], u.Query().Get("ep"), nil
+	default:
+		return "", "", fmt.Errorf("unsupported link: %s", link)
+	}
+}
+
+// ValidateAnimeRef checks that input is a well-formed slug, numeric ID, episode ID or
+// hianime link without looking anything up
+func (s *Scraper) ValidateAnimeRef(input string) error {
+	_, err := parseAnimeRef(input)
+	return err
+}
+
+// parseEpisodeID splits an episode ID of the form "slug::ep=N" into the anime ID
+// and the episode ID
+func parseEpisodeID(episodeID string) (string, string, error) {
+	animeID, episode, found := strings.Cut(episodeID, "::ep=")
+	if !found || !numericRegex.MatchString(episode) {
+		return "", "", fmt.Errorf("invalid episode ID format: %s", episodeID)
+	}
+	if _, err := numericID(animeID); err != nil {
+		return "", "", fmt.Errorf("invalid episode ID format: %s", episodeID)
+	}
+	return animeID, episode, nil
+}
+
+// ValidateEpisodeID checks that episodeID has the "slug::ep=N" shape without looking
+// anything up
+func (s *Scraper) ValidateEpisodeID(episodeID string) error {
+	_, _, err := parseEpisodeID(episodeID)
+	return err
+}
+
+// Resolve turns a slug, bare numeric ID, episode ID or hianime link into the
+// canonical anime ID. The slug is always looked up on the site by numeric ID, so
+// outdated or misspelled slugs resolve to the current one.
+func (s *Scraper) Resolve(input string) (*models.ResolveResponse, error) {
+	ref, err := parseAnimeRef(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Slug, err = s.resolveSlug(ref.NumericID); err != nil {
+		return nil, err
+	}
+
+	response := &models.ResolveResponse{
+		Input:     strings.TrimSpace(input),
+		AnimeID:   ref.Slug,
+		NumericID: ref.NumericID,
+		URL:       s.buildURL(nil, ref.Slug),
+	}
+	if ref.Episode != "" {
+		response.EpisodeID = fmt.Sprintf("%s::ep=%s", ref.Slug, ref.Episode)
+		response.URL = s.watchURL(ref.Slug, ref.Episode)
+	}
+
+	return response, nil
+}
+
+// resolveSlug looks up the slug of a numeric anime ID through the qtip endpoint,
+// whose play button links to the anime's watch page
+func (s *Scraper) resolveSlug(numeric string) (string, error) {
+	if slug, ok := s.slugs.Get(numeric); ok {
+		return slug, nil
+	}
+
+	qtip, err := s.GetAnimeQtipInfo(numeric)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve anime id %s: %w", numeric, err)
+	}
+
+	slug := qtip.Anime.ID
+	if id, err := numericID(slug); err != nil || id != numeric || slug == numeric {
+		return "", fmt.Errorf("%w: %s", ErrAnimeNotFound, numeric)
+	}
+
+	s.slugs.Set(numeric, slug)
+	return slug, nil
+}
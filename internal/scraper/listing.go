@@ -16,16 +16,12 @@ func (s *Scraper) AnimeList(category string, page int) (*models.ListPageResponse
 		page = 1
 	}
 
-	var url string
-	switch category {
-	case "most-popular", "top-airing", "most-favorite", "completed", "recently-added", "recently-updated", "top-upcoming":
-		url = fmt.Sprintf("%s/%s?page=%d", s.config.BaseURL, category, page)
-	case "subbed-anime", "dubbed-anime", "movie", "tv", "ova", "ona", "special", "events":
-		url = fmt.Sprintf("%s/%s?page=%d", s.config.BaseURL, category, page)
-	default:
-		return nil, fmt.Errorf("unsupported category: %s", category)
+	if err := s.ValidateCategory(category); err != nil {
+		return nil, err
 	}
 
+	url := s.buildURL(pageQuery(page), strings.ToLower(category))
+
 	resp, err := s.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -47,7 +43,7 @@ func (s *Scraper) AnimeList(category string, page int) (*models.ListPageResponse
 	}
 
 	// Extract anime list
-	response.Results = s.extractAnimes(doc, ".film_list .film_list-wrap .flw-item")
+	response.Results = parseCards(doc.Selection, ".film_list .film_list-wrap .flw-item", filmCard)
 
 	// Check if there's a next page
 	response.HasNextPage = doc.Find(".pagination .next").Length() > 0
@@ -61,7 +57,12 @@ func (s *Scraper) GenreList(genre string, page int) (*models.ListPageResponse, e
 		page = 1
 	}
 
-	url := fmt.Sprintf("%s/genre/%s?page=%d", s.config.BaseURL, strings.ToLower(genre), page)
+	if err := s.ValidateGenre(genre); err != nil {
+		return nil, err
+	}
+
+	// Genre pages use lowercase, hyphenated slugs such as "slice-of-life"
+	url := s.buildURL(pageQuery(page), "genre", genreSlug(genre))
 
 	resp, err := s.client.Get(url)
 	if err != nil {
@@ -84,7 +85,7 @@ func (s *Scraper) GenreList(genre string, page int) (*models.ListPageResponse, e
 	}
 
 	// Extract anime list
-	response.Results = s.extractAnimes(doc, ".film_list .film_list-wrap .flw-item")
+	response.Results = parseCards(doc.Selection, ".film_list .film_list-wrap .flw-item", filmCard)
 
 	// Check if there's a next page
 	response.HasNextPage = doc.Find(".pagination .next").Length() > 0
@@ -0,0 +1,174 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// CharacterDetails scrapes a character page with its bio, voice actors and animeography
+func (s *Scraper) CharacterDetails(characterID string) (*models.CharacterDetailResponse, error) {
+	doc, err := s.fetchProfilePage("character", characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.CharacterDetailResponse{
+		ID:           characterID,
+		VoiceActors:  []models.VoiceActor{},
+		Animeography: []models.FilmographyEntry{},
+	}
+	response.Name, response.JName, response.Picture, response.Bio = parseProfile(doc)
+
+	if response.Name == "" {
+		return nil, fmt.Errorf("character not found: %s", characterID)
+	}
+
+	// Voice actors are listed as people links with their language
+	doc.Find(".sub-box-list .per-info, .block-actors-content .per-info").Each(func(i int, sel *goquery.Selection) {
+		href := sel.Find(".pi-avatar").AttrOr("href", "")
+		if !strings.Contains(href, "/people/") {
+			return
+		}
+
+		actor := models.VoiceActor{
+			ID:       pathID(href),
+			Name:     strings.TrimSpace(sel.Find(".pi-name").Text()),
+			Picture:  imageSource(sel.Find(".pi-avatar img")),
+			Language: strings.TrimSpace(sel.Find(".pi-cast").Text()),
+		}
+		if actor.Name != "" {
+			response.VoiceActors = append(response.VoiceActors, actor)
+		}
+	})
+
+	response.Animeography = parseFilmography(doc)
+
+	return response, nil
+}
+
+// PersonDetails scrapes a voice actor page with its bio and every anime and character role
+func (s *Scraper) PersonDetails(personID string) (*models.PersonDetailResponse, error) {
+	doc, err := s.fetchProfilePage("people", personID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.PersonDetailResponse{
+		ID:    personID,
+		Roles: []models.FilmographyEntry{},
+	}
+	response.Name, response.JName, response.Picture, response.Bio = parseProfile(doc)
+
+	if response.Name == "" {
+		return nil, fmt.Errorf("person not found: %s", personID)
+	}
+
+	response.Language = strings.TrimSpace(doc.Find(".actor-page-wrap .apw-detail .lang, .apw-detail .sub-lang").First().Text())
+	response.Roles = parseFilmography(doc)
+
+	return response, nil
+}
+
+// fetchProfilePage fetches a character or people page
+func (s *Scraper) fetchProfilePage(kind, id string) (*goquery.Document, error) {
+	id = strings.Trim(strings.TrimSpace(id), "/")
+	if id == "" {
+		return nil, fmt.Errorf("invalid %s id", kind)
+	}
+
+	url := s.buildURL(nil, kind, id)
+
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return doc, nil
+}
+
+// parseProfile extracts the name, Japanese name, picture and bio shared by character and people pages
+func parseProfile(doc *goquery.Document) (name, jname, picture, bio string) {
+	detail := doc.Find(".actor-page-wrap .apw-detail")
+
+	name = strings.TrimSpace(detail.Find(".name").First().Text())
+	jname = strings.TrimSpace(detail.Find(".sub-name").First().Text())
+	picture = imageSource(doc.Find(".actor-page-wrap .avatar img").First())
+
+	bioSel := doc.Find("#bio .bio")
+	if bioSel.Length() == 0 {
+		bioSel = detail.Find(".bio")
+	}
+	bio = strings.TrimSpace(bioSel.First().Text())
+
+	return name, jname, picture, bio
+}
+
+// parseFilmography extracts anime appearances from .bac-item blocks. Each block pairs an
+// anime with, on people pages, the character voiced and its role.
+func parseFilmography(doc *goquery.Document) []models.FilmographyEntry {
+	entries := []models.FilmographyEntry{}
+
+	doc.Find(".bac-list-wrap .bac-item").Each(func(i int, item *goquery.Selection) {
+		entry := models.FilmographyEntry{}
+
+		item.Find(".per-info").Each(func(j int, info *goquery.Selection) {
+			avatar := info.Find(".pi-avatar")
+			href := avatar.AttrOr("href", "")
+			if href == "" {
+				href = info.Find(".pi-name a").AttrOr("href", "")
+			}
+			name := strings.TrimSpace(info.Find(".pi-name").Text())
+			cast := strings.TrimSpace(info.Find(".pi-cast").Text())
+
+			switch {
+			case strings.Contains(href, "/character/"):
+				entry.CharacterID = pathID(href)
+				entry.CharacterName = name
+				entry.CharacterPicture = imageSource(avatar.Find("img"))
+				entry.Role = cast
+			case strings.Contains(href, "/people/"):
+				// Voice actor blocks on character pages are not filmography
+			case href != "":
+				entry.AnimeID = pathID(href)
+				entry.AnimeTitle = name
+				entry.AnimePoster = imageSource(avatar.Find("img"))
+				// Character pages show the role under the anime, people pages the type
+				if isCharacterRole(cast) {
+					entry.Role = cast
+				} else {
+					entry.AnimeType = cast
+				}
+			}
+		})
+
+		if entry.AnimeID != "" {
+			entries = append(entries, entry)
+		}
+	})
+
+	return entries
+}
+
+// isCharacterRole reports whether text is a character role such as "Main" or "Supporting"
+func isCharacterRole(text string) bool {
+	switch strings.ToLower(text) {
+	case "main", "supporting", "background":
+		return true
+	}
+	return false
+}
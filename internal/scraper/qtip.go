@@ -12,22 +12,15 @@ import (
 
 // GetAnimeQtipInfo scrapes anime qtip information by ID
 func (s *Scraper) GetAnimeQtipInfo(animeID string) (*models.QtipResponse, error) {
-	// Validate anime ID format
+	// Validate the anime ID and extract its numeric part
 	animeID = strings.TrimSpace(animeID)
-	if animeID == "" || !strings.Contains(animeID, "-") {
-		return nil, fmt.Errorf("invalid anime id: %s", animeID)
-	}
-
-	// Extract the numeric ID from the anime ID (last part after splitting by "-")
-	parts := strings.Split(animeID, "-")
-	if len(parts) == 0 {
-		return nil, fmt.Errorf("invalid anime id format: %s", animeID)
+	id, err := numericID(animeID)
+	if err != nil {
+		return nil, err
 	}
-	id := parts[len(parts)-1]
 
 	// Construct the qtip URL
-	qtipURL := fmt.Sprintf("/ajax/movie/qtip/%s", id)
-	url := s.config.BaseURL + qtipURL
+	url := s.buildURL(nil, "ajax", "movie", "qtip", id)
 
 	// Make the HTTP request with proper headers
 	resp, err := s.client.GetWithHeaders(url, map[string]string{
@@ -59,15 +52,14 @@ func (s *Scraper) GetAnimeQtipInfo(animeID string) (*models.QtipResponse, error)
 	qtipContent := doc.Find(selector)
 
 	if qtipContent.Length() == 0 {
-		return nil, fmt.Errorf("qtip content not found")
+		return nil, fmt.Errorf("%w: qtip content not found for %s", ErrAnimeNotFound, id)
 	}
 
 	// Extract ID from the play button href
 	playButton := qtipContent.Find(".pre-qtip-button a.btn-play")
 	if href, exists := playButton.Attr("href"); exists {
-		parts := strings.Split(strings.TrimSpace(href), "/")
-		if len(parts) > 0 {
-			response.Anime.ID = parts[len(parts)-1]
+		if id := pathID(href); id != "" {
+			response.Anime.ID = id
 		}
 	}
 
@@ -3,44 +3,226 @@ package scraper
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	_ "time/tzdata" // IANA names must resolve on hosts without a zoneinfo database
 
 	"github.com/ayanrajpoot10/hianime-api/pkg/models"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-// GetEstimatedSchedule scrapes estimated schedule for a specific date
-func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.EstimatedScheduleResponse, error) {
-	if s.config.Verbose {
-		fmt.Printf("Fetching estimated schedule for date: %s (timezone offset: %d)\n", date, tzOffset)
+// scheduleDateLayout is the date format of the schedule endpoints
+const scheduleDateLayout = "2006-01-02"
+
+// scheduleWeekDays is the number of days covered by GetWeeklySchedule
+const scheduleWeekDays = 7
+
+// ParseTimezone accepts an IANA timezone name such as "Europe/Berlin", or a site-style
+// offset in minutes behind UTC such as "-330" for India Standard Time
+func ParseTimezone(value string) (*time.Location, error) {
+	value = strings.TrimSpace(value)
+	if tzOffset, err := strconv.Atoi(value); err == nil {
+		if tzOffset < -14*60 || tzOffset > 14*60 {
+			return nil, fmt.Errorf("timezone offset out of range: %d", tzOffset)
+		}
+		return offsetLocation(tzOffset), nil
 	}
 
-	// Validate date format (YYYY-MM-DD)
-	date = strings.TrimSpace(date)
-	datePattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
-	if date == "" || !datePattern.MatchString(date) {
-		return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %s", date)
+	if value == "" || value == "Local" {
+		return nil, fmt.Errorf("unknown timezone: %q", value)
+	}
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone: %s", value)
 	}
+	return loc, nil
+}
 
-	// Validate timezone offset
+// offsetLocation returns a fixed zone for a site-style offset in minutes behind UTC
+func offsetLocation(tzOffset int) *time.Location {
 	if tzOffset == 0 {
-		tzOffset = -330 // Default timezone offset (India Standard Time)
+		return time.UTC
+	}
+	minutes, sign := -tzOffset, "+"
+	if minutes < 0 {
+		minutes, sign = -minutes, "-"
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%s%02d:%02d", sign, minutes/60, minutes%60), -tzOffset*60)
+}
+
+// parseScheduleDate parses a YYYY-MM-DD date as midnight in loc
+func parseScheduleDate(date string, loc *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation(scheduleDateLayout, strings.TrimSpace(date), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %s", date)
+	}
+	return day, nil
+}
+
+// ValidateScheduleDate checks that date is a YYYY-MM-DD date without fetching anything
+func (s *Scraper) ValidateScheduleDate(date string) error {
+	_, err := parseScheduleDate(date, time.UTC)
+	return err
+}
+
+// GetEstimatedSchedule scrapes estimated schedule for a specific date. tzOffset is in
+// minutes behind UTC, as JavaScript's getTimezoneOffset reports it (-330 for IST).
+func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.EstimatedScheduleResponse, error) {
+	return s.GetScheduleIn(date, offsetLocation(tzOffset))
+}
+
+// GetScheduleIn scrapes estimated schedule for a specific date in a timezone
+func (s *Scraper) GetScheduleIn(date string, loc *time.Location) (*models.EstimatedScheduleResponse, error) {
+	day, err := parseScheduleDate(date, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	animes, err := s.scheduleDay(day)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EstimatedScheduleResponse{
+		Date:            day.Format(scheduleDateLayout),
+		Timezone:        loc.String(),
+		ScheduledAnimes: animes,
+	}, nil
+}
+
+// GetWeeklySchedule scrapes the estimated schedule of seven days starting at date,
+// fetching the days concurrently and grouping the animes by day. An empty date
+// starts today in loc.
+func (s *Scraper) GetWeeklySchedule(date string, loc *time.Location) (*models.WeeklyScheduleResponse, error) {
+	now := time.Now().In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if date != "" {
+		var err error
+		if start, err = parseScheduleDate(date, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	days := make([]models.ScheduleDay, scheduleWeekDays)
+	var wg sync.WaitGroup
+	for i := range days {
+		day := start.AddDate(0, 0, i)
+		days[i] = models.ScheduleDay{
+			Date:            day.Format(scheduleDateLayout),
+			Weekday:         day.Weekday().String(),
+			ScheduledAnimes: []models.ScheduledAnime{},
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			animes, err := s.scheduleDay(day)
+			if err != nil {
+				days[i].Error = err.Error()
+				return
+			}
+			days[i].ScheduledAnimes = animes
+		}()
+	}
+	wg.Wait()
+
+	response := &models.WeeklyScheduleResponse{
+		Timezone:  loc.String(),
+		StartDate: days[0].Date,
+		EndDate:   days[len(days)-1].Date,
+		Days:      days,
+	}
+
+	failed := 0
+	for _, day := range days {
+		if day.Error != "" {
+			failed++
+		}
+		response.TotalItems += len(day.ScheduledAnimes)
+	}
+	if failed == len(days) {
+		return nil, fmt.Errorf("failed to fetch schedule: %s", days[0].Error)
+	}
+
+	return response, nil
+}
+
+// scheduleDay scrapes the schedule of the day starting at midnight day. The site only
+// takes a fixed offset, so on a day with a DST change the schedule is fetched at the
+// offsets in effect at both ends of the day, and each airing is kept when its instant
+// falls within the day in day's timezone.
+func (s *Scraper) scheduleDay(day time.Time) ([]models.ScheduledAnime, error) {
+	end := day.AddDate(0, 0, 1)
+	_, startOffset := day.Zone()
+	_, endOffset := end.Add(-time.Second).Zone()
+
+	animes, err := s.scheduleAt(day, -startOffset/60)
+	if err != nil {
+		return nil, err
+	}
+	if endOffset == startOffset {
+		return animes, nil
+	}
+
+	later, err := s.scheduleAt(day, -endOffset/60)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	merged := []models.ScheduledAnime{}
+	for _, anime := range append(animes, later...) {
+		if anime.AiringTimestamp != 0 {
+			at := time.UnixMilli(anime.AiringTimestamp)
+			if at.Before(day) || !at.Before(end) {
+				continue
+			}
+			// Either fetch may have rendered the time at the other side of the change
+			anime.Time = at.In(day.Location()).Format("15:04")
+		}
+
+		key := fmt.Sprintf("%s/%d/%d", anime.ID, anime.Episode, anime.AiringTimestamp)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, anime)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].AiringTimestamp < merged[j].AiringTimestamp
+	})
+	return merged, nil
+}
+
+// scheduleAt scrapes the schedule of day's date as the site renders it at tzOffset,
+// in minutes behind UTC. Airing times are read at that offset and then converted to
+// day's timezone.
+func (s *Scraper) scheduleAt(day time.Time, tzOffset int) ([]models.ScheduledAnime, error) {
+	date := day.Format(scheduleDateLayout)
+	siteZone := offsetLocation(tzOffset)
+
+	if s.config.Verbose {
+		fmt.Printf("Fetching estimated schedule for date: %s (timezone offset: %d)\n", date, tzOffset)
 	}
 
 	// Construct the schedule URL
-	scheduleURL := fmt.Sprintf("/ajax/schedule/list?tzOffset=%d&date=%s", tzOffset, date)
-	url := s.config.BaseURL + scheduleURL
+	requestURL := s.buildURL(url.Values{
+		"tzOffset": {strconv.Itoa(tzOffset)},
+		"date":     {date},
+	}, "ajax", "schedule", "list")
 
 	if s.config.Verbose {
-		fmt.Printf("Making request to: %s\n", url)
+		fmt.Printf("Making request to: %s\n", requestURL)
 	}
 
 	// Make the HTTP request with proper headers
-	resp, err := s.client.GetWithHeaders(url, map[string]string{
+	resp, err := s.client.GetWithHeaders(requestURL, map[string]string{
 		"Accept":           "*/*",
 		"Referer":          s.config.BaseURL,
 		"X-Requested-With": "XMLHttpRequest",
@@ -65,16 +247,15 @@ func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.Estim
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	// Initialize the response
-	response := &models.EstimatedScheduleResponse{
-		ScheduledAnimes: []models.ScheduledAnime{},
-	}
+	animes := []models.ScheduledAnime{}
 
 	// Check if there's no data
 	if strings.Contains(doc.Text(), "No data to display") {
-		return response, nil
+		return animes, nil
 	}
 
+	now := time.Now()
+
 	// Extract scheduled animes from li elements
 	doc.Find("li").Each(func(i int, sel *goquery.Selection) {
 		anime := models.ScheduledAnime{}
@@ -82,8 +263,7 @@ func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.Estim
 		// Extract anime ID from href attribute
 		link := sel.Find("a")
 		if href, exists := link.Attr("href"); exists {
-			// Remove leading slash and trim
-			anime.ID = strings.TrimPrefix(strings.TrimSpace(href), "/")
+			anime.ID = pathID(href)
 		}
 
 		// Extract time
@@ -100,15 +280,12 @@ func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.Estim
 			anime.JName = strings.TrimSpace(jname)
 		}
 
-		// Calculate airing timestamp
+		// Calculate airing timestamp from the date and HH:MM time at the site's offset
 		if timeText != "" {
-			// Create timestamp from date and time (assuming format HH:MM)
-			timestampStr := fmt.Sprintf("%sT%s:00", date, timeText)
-			if airingTime, err := time.Parse("2006-01-02T15:04:05", timestampStr); err == nil {
+			if airingTime, err := time.ParseInLocation("2006-01-02 15:04", date+" "+timeText, siteZone); err == nil {
+				anime.AiringISOTimestamp = airingTime.In(day.Location()).Format(time.RFC3339)
 				anime.AiringTimestamp = airingTime.UnixMilli()
-				// Calculate seconds until airing
-				now := time.Now().UnixMilli()
-				anime.SecondsUntilAiring = (anime.AiringTimestamp - now) / 1000
+				anime.SecondsUntilAiring = int64(airingTime.Sub(now).Seconds())
 			}
 		}
 
@@ -127,11 +304,11 @@ func (s *Scraper) GetEstimatedSchedule(date string, tzOffset int) (*models.Estim
 
 		// Only add if we have at least an ID
 		if anime.ID != "" {
-			response.ScheduledAnimes = append(response.ScheduledAnimes, anime)
+			animes = append(animes, anime)
 		}
 	})
 
-	return response, nil
+	return animes, nil
 }
 
 // GetNextEpisodeSchedule scrapes the next episode schedule for a specific anime
@@ -147,15 +324,14 @@ func (s *Scraper) GetNextEpisodeSchedule(animeID string) (*models.NextEpisodeSch
 	}
 
 	// Construct the anime watch URL
-	animeURL := fmt.Sprintf("/watch/%s", animeID)
-	url := s.config.BaseURL + animeURL
+	requestURL := s.watchURL(animeID, "")
 
 	if s.config.Verbose {
-		fmt.Printf("Making request to: %s\n", url)
+		fmt.Printf("Making request to: %s\n", requestURL)
 	}
 
 	// Make the HTTP request with proper headers
-	resp, err := s.client.GetWithHeaders(url, map[string]string{
+	resp, err := s.client.GetWithHeaders(requestURL, map[string]string{
 		"Accept":  "*/*",
 		"Referer": s.config.BaseURL,
 	})
@@ -170,9 +346,15 @@ func (s *Scraper) GetNextEpisodeSchedule(animeID string) (*models.NextEpisodeSch
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	// Initialize the response
+	return parseNextAiring(doc), nil
+}
+
+// parseNextAiring extracts the next-airing banner of a watch page
+func parseNextAiring(doc *goquery.Document) *models.NextEpisodeScheduleResponse {
 	response := &models.NextEpisodeScheduleResponse{}
 
+	response.Banner = strings.Join(strings.Fields(doc.Find(".schedule-alert > .alert.small").Text()), " ")
+
 	// Extract timestamp from the schedule alert
 	selector := ".schedule-alert > .alert.small > span:last-child"
 	scheduleSpan := doc.Find(selector)
@@ -196,5 +378,5 @@ func (s *Scraper) GetNextEpisodeSchedule(animeID string) (*models.NextEpisodeSch
 		}
 	}
 
-	return response, nil
+	return response
 }
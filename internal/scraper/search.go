@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/ayanrajpoot10/hianime-api/pkg/models"
@@ -17,9 +18,11 @@ func (s *Scraper) Search(keyword string, page int) (*models.SearchResponse, erro
 		page = 1
 	}
 
-	url := fmt.Sprintf("%s/search?keyword=%s&page=%d", s.config.BaseURL, strings.ReplaceAll(keyword, " ", "+"), page)
+	query := pageQuery(page)
+	query.Set("keyword", keyword)
+	requestURL := s.buildURL(query, "search")
 
-	resp, err := s.client.Get(url)
+	resp, err := s.client.Get(requestURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -39,7 +42,7 @@ func (s *Scraper) Search(keyword string, page int) (*models.SearchResponse, erro
 	}
 
 	// Extract search results
-	response.Results = s.extractAnimes(doc, ".film_list .film_list-wrap .flw-item")
+	response.Results = parseCards(doc.Selection, ".film_list .film_list-wrap .flw-item", filmCard)
 
 	// Check if there's a next page
 	response.HasNextPage = doc.Find(".pagination .next").Length() > 0
@@ -49,13 +52,13 @@ func (s *Scraper) Search(keyword string, page int) (*models.SearchResponse, erro
 
 // Suggestions scrapes search suggestions based on keyword
 func (s *Scraper) Suggestions(keyword string) (*models.SearchResponse, error) {
-	url := fmt.Sprintf("%s/ajax/search/suggest?keyword=%s", s.config.BaseURL, strings.ReplaceAll(keyword, " ", "+"))
+	requestURL := s.buildURL(url.Values{"keyword": {keyword}}, "ajax", "search", "suggest")
 
 	headers := map[string]string{
 		"X-Requested-With": "XMLHttpRequest",
 	}
 
-	resp, err := s.client.GetWithHeaders(url, headers)
+	resp, err := s.client.GetWithHeaders(requestURL, headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -100,27 +103,7 @@ func (s *Scraper) Suggestions(keyword string) (*models.SearchResponse, error) {
 	}
 
 	// Extract suggestions
-	doc.Find(".nav-item").Each(func(i int, sel *goquery.Selection) {
-		item := models.AnimeItem{}
-
-		// Extract ID and title
-		linkEl := sel.Find("a")
-		href, exists := linkEl.Attr("href")
-		if exists {
-			parts := strings.Split(href, "/")
-			if len(parts) > 0 {
-				item.ID = parts[len(parts)-1]
-			}
-		}
-
-		item.Title = strings.TrimSpace(linkEl.Find(".srp-detail .film-name").Text())
-		item.Poster, _ = linkEl.Find(".film-poster img").Attr("data-src")
-
-		// Extract type and year
-		item.Type = strings.TrimSpace(linkEl.Find(".srp-detail .film-infor span").First().Text())
-
-		response.Results = append(response.Results, item)
-	})
+	response.Results = parseCards(doc.Selection, ".nav-item", suggestionCard)
 
 	return response, nil
 }
@@ -0,0 +1,159 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ayanrajpoot10/hianime-api/internal/decrypt"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// skipTolerance is how far apart, in seconds, two reports of a marker may be and still agree
+const skipTolerance = 5
+
+// SkipTimes gathers intro, outro, recap and credits markers for an episode from every
+// sub and dub server and reconciles them into a single set of skip ranges
+func (s *Scraper) SkipTimes(episodeID string) (*models.SkipTimesResponse, error) {
+	if s.config.EnableCache {
+		if cached, ok := s.skipTimes.Get(episodeID); ok {
+			return cached, nil
+		}
+	}
+
+	servers, err := s.Servers(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	candidates := append(s.orderServers(servers.Sub), s.orderServers(servers.Dub)...)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no servers available for episode: %s", episodeID)
+	}
+
+	decryptor := decrypt.NewMegacloudDecryptor(s.client, s.config)
+
+	results := make([][]models.SkipSource, len(candidates))
+	var wg sync.WaitGroup
+	for i := range candidates {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = decryptor.SkipSources(&candidates[i], episodeID)
+		}(i)
+	}
+	wg.Wait()
+
+	response := &models.SkipTimesResponse{
+		EpisodeID: episodeID,
+		Sources:   []models.SkipSource{},
+	}
+	for _, sources := range results {
+		response.Sources = append(response.Sources, sources...)
+	}
+
+	pick := func(marker func(models.SkipSource) *models.TimeRange) *models.SkipRange {
+		return reconcileSkipRanges(response.Sources, marker)
+	}
+	response.Intro = pick(func(src models.SkipSource) *models.TimeRange { return src.Intro })
+	response.Outro = pick(func(src models.SkipSource) *models.TimeRange { return src.Outro })
+	response.Recap = pick(func(src models.SkipSource) *models.TimeRange { return src.Recap })
+	response.Credits = pick(func(src models.SkipSource) *models.TimeRange { return src.Credits })
+
+	// Only cache once at least one server answered
+	answered := false
+	for _, src := range response.Sources {
+		answered = answered || src.Error == ""
+	}
+	if !answered {
+		return nil, fmt.Errorf("all servers failed: %s", response.Sources[0].Error)
+	}
+
+	if s.config.EnableCache {
+		s.skipTimes.Set(episodeID, response)
+	}
+
+	return response, nil
+}
+
+// reconcileSkipRanges groups the reports of one marker into clusters that agree within
+// skipTolerance and returns the median range of the largest cluster. Ties go to the
+// cluster reported first, which follows the server preference order.
+func reconcileSkipRanges(sources []models.SkipSource, marker func(models.SkipSource) *models.TimeRange) *models.SkipRange {
+	type report struct {
+		tr     *models.TimeRange
+		server string
+	}
+
+	var clusters [][]report
+	total := 0
+
+	for _, src := range sources {
+		tr := marker(src)
+		if tr.IsEmpty() {
+			continue
+		}
+		total++
+
+		r := report{tr: tr, server: fmt.Sprintf("%s (%s)", src.Server, src.Type)}
+		placed := false
+		for i, cluster := range clusters {
+			first := cluster[0].tr
+			if abs(first.Start-tr.Start) <= skipTolerance && abs(first.End-tr.End) <= skipTolerance {
+				clusters[i] = append(cluster, r)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, []report{r})
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	best := clusters[0]
+	for _, cluster := range clusters[1:] {
+		if len(cluster) > len(best) {
+			best = cluster
+		}
+	}
+
+	var starts, ends []int
+	var servers []string
+	seen := make(map[string]bool)
+	for _, r := range best {
+		starts = append(starts, r.tr.Start)
+		ends = append(ends, r.tr.End)
+		if !seen[r.server] {
+			seen[r.server] = true
+			servers = append(servers, r.server)
+		}
+	}
+
+	return &models.SkipRange{
+		Start:      median(starts),
+		End:        median(ends),
+		Votes:      len(best),
+		Confidence: float64(len(best)) / float64(total),
+		Servers:    servers,
+		Conflict:   len(clusters) > 1,
+	}
+}
+
+// median returns the lower median of values
+func median(values []int) int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	return sorted[(len(sorted)-1)/2]
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
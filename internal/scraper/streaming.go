@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -27,14 +29,14 @@ func (s *Scraper) Servers(episodeID string) (*models.ServersResponse, error) {
 
 	episodeNum := epParts[1]
 
-	url := fmt.Sprintf("%s/ajax/v2/episode/servers?episodeId=%s", s.config.BaseURL, episodeNum)
+	requestURL := s.buildURL(url.Values{"episodeId": {episodeNum}}, "ajax", "v2", "episode", "servers")
 
 	headers := map[string]string{
-		"Referer":          fmt.Sprintf("%s/watch/%s", s.config.BaseURL, strings.ReplaceAll(episodeID, "::", "?")),
+		"Referer":          s.watchURL(epParts[0], episodeNum),
 		"X-Requested-With": "XMLHttpRequest",
 	}
 
-	resp, err := s.client.GetWithHeaders(url, headers)
+	resp, err := s.client.GetWithHeaders(requestURL, headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -73,72 +75,210 @@ func (s *Scraper) Servers(episodeID string) (*models.ServersResponse, error) {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	response := &models.ServersResponse{}
+	return parseServers(doc, episodeNum), nil
+}
+
+// parseServers extracts the servers of every type offered in the servers block,
+// along with the notices shown above them
+func parseServers(doc *goquery.Document, episodeNum string) *models.ServersResponse {
+	response := &models.ServersResponse{
+		Types: []string{},
+	}
 
 	// Parse episode number
 	if epNum, err := strconv.Atoi(episodeNum); err == nil {
 		response.Episode = epNum
 	}
 
-	// Extract sub servers
-	doc.Find(".ps_-block .ps__-list .server-item[data-type='sub']").Each(func(i int, sel *goquery.Selection) {
+	doc.Find(".ps_-block .ps__-list .server-item").Each(func(i int, sel *goquery.Selection) {
+		serverType := strings.ToLower(strings.TrimSpace(sel.AttrOr("data-type", "")))
+		if serverType == "" {
+			// Older markup only names the type in the block class, e.g. "servers-raw"
+			block := sel.Closest(".ps_-block").AttrOr("class", "")
+			for _, class := range strings.Fields(block) {
+				if strings.HasPrefix(class, "servers-") {
+					serverType = strings.TrimPrefix(class, "servers-")
+				}
+			}
+		}
+		if serverType == "" {
+			return
+		}
+
 		server := models.Server{
-			Type:  "sub",
-			Index: i,
+			Type:  serverType,
+			Index: len(response.List(serverType)),
 		}
 
 		server.Name = strings.TrimSpace(sel.Text())
 		server.ID, _ = sel.Attr("data-id")
 
-		response.Sub = append(response.Sub, server)
+		response.Add(server)
 	})
 
-	// Extract dub servers
-	doc.Find(".ps_-block .ps__-list .server-item[data-type='dub']").Each(func(i int, sel *goquery.Selection) {
-		server := models.Server{
-			Type:  "dub",
-			Index: i,
-		}
+	response.Notices = parseNotices(doc.Find(".server-notice"))
 
-		server.Name = strings.TrimSpace(sel.Text())
-		server.ID, _ = sel.Attr("data-id")
+	return response
+}
 
-		response.Dub = append(response.Dub, server)
+// parseNotices returns the distinct, whitespace-normalised texts of the selection
+func parseNotices(sel *goquery.Selection) []string {
+	var notices []string
+	sel.Each(func(i int, notice *goquery.Selection) {
+		text := strings.Join(strings.Fields(notice.Text()), " ")
+		if text != "" && !contains(notices, text) {
+			notices = append(notices, text)
+		}
 	})
+	return notices
+}
+
+// StreamLinks scrapes streaming links for a specific episode and server using megacloud decryption.
+// A server name of "auto" tries every available server in preference order. With verify the
+// playlist is checked before returning.
+func (s *Scraper) StreamLinks(episodeID, serverType, serverName string, verify bool) (*models.StreamResponse, error) {
+	if strings.EqualFold(serverName, "auto") {
+		return s.AutoStreamLinks(episodeID, serverType, s.config.DubFallback, verify)
+	}
+
+	selectedServer, err := s.findServer(episodeID, serverType, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create megacloud decryptor and use it
+	decryptor := decrypt.NewMegacloudDecryptor(s.client, s.config)
+	response, err := decryptor.Decrypt(selectedServer, episodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verification only annotates the response, the stream is returned either way
+	if verify {
+		s.VerifyStream(response)
+	}
 
 	return response, nil
 }
 
-// StreamLinks scrapes streaming links for a specific episode and server using megacloud decryption
-func (s *Scraper) StreamLinks(episodeID, serverType, serverName string) (*models.StreamResponse, error) {
-	// First get the servers to find the server ID
+// TokenDiagnostics lists every token candidate found on the embed page of an episode server
+func (s *Scraper) TokenDiagnostics(episodeID, serverType, serverName string) (*models.TokenDiagnostics, error) {
+	selectedServer, err := s.findServer(episodeID, serverType, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	decryptor := decrypt.NewMegacloudDecryptor(s.client, s.config)
+	return decryptor.InspectToken(selectedServer)
+}
+
+// EmbedTokenDiagnostics lists every token candidate found on an embed page URL
+func (s *Scraper) EmbedTokenDiagnostics(embedURL string) (*models.TokenDiagnostics, error) {
+	decryptor := decrypt.NewMegacloudDecryptor(s.client, s.config)
+	return decryptor.InspectTokenURL(embedURL)
+}
+
+// FallbackStats returns the success rates of the megacloud fallback hosts
+func (s *Scraper) FallbackStats() *models.FallbackStatsResponse {
+	return &models.FallbackStatsResponse{
+		Hosts: decrypt.FallbackStats(s.config),
+	}
+}
+
+// findServer looks up a server of an episode by type and name
+func (s *Scraper) findServer(episodeID, serverType, serverName string) (*models.Server, error) {
 	servers, err := s.Servers(episodeID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get servers: %w", err)
 	}
 
-	var selectedServer *models.Server
-	if strings.ToLower(serverType) == "sub" {
-		for _, server := range servers.Sub {
-			if strings.EqualFold(server.Name, serverName) {
-				selectedServer = &server
-				break
-			}
+	for _, server := range servers.List(serverType) {
+		if strings.EqualFold(server.Name, serverName) {
+			return &server, nil
 		}
-	} else {
-		for _, server := range servers.Dub {
-			if strings.EqualFold(server.Name, serverName) {
-				selectedServer = &server
-				break
-			}
+	}
+
+	return nil, fmt.Errorf("server not found: %s (%s)", serverName, serverType)
+}
+
+// AutoStreamLinks tries every server of the requested type in the configured preference
+// order and returns the first working stream. With dubFallback, sub servers are tried
+// after all dub servers failed, and with verify a server only counts as working once its
+// playlist and first segment are reachable. The response lists the servers that failed and why.
+func (s *Scraper) AutoStreamLinks(episodeID, serverType string, dubFallback, verify bool) (*models.StreamResponse, error) {
+	servers, err := s.Servers(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", err)
+	}
+
+	var candidates []models.Server
+	switch strings.ToLower(serverType) {
+	case "", "sub":
+		candidates = append(candidates, s.orderServers(servers.Sub)...)
+	case "dub":
+		candidates = append(candidates, s.orderServers(servers.Dub)...)
+		if dubFallback {
+			candidates = append(candidates, s.orderServers(servers.Sub)...)
 		}
+	default:
+		candidates = append(candidates, s.orderServers(servers.List(serverType))...)
 	}
 
-	if selectedServer == nil {
-		return nil, fmt.Errorf("server not found: %s (%s)", serverName, serverType)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no servers available for episode: %s (%s)", episodeID, serverType)
 	}
 
-	// Create megacloud decryptor and use it
 	decryptor := decrypt.NewMegacloudDecryptor(s.client, s.config)
-	return decryptor.Decrypt(selectedServer, episodeID)
+
+	var attempts []models.StreamAttempt
+	for _, server := range candidates {
+		response, err := decryptor.Decrypt(&server, episodeID)
+		if err == nil && response.Link.File == "" {
+			err = fmt.Errorf("empty stream link")
+		}
+		if err == nil && verify {
+			err = s.VerifyStream(response)
+		}
+		if err == nil {
+			response.Attempts = attempts
+			return response, nil
+		}
+
+		if s.config.Verbose {
+			fmt.Printf("Server %s (%s) failed: %v\n", server.Name, server.Type, err)
+		}
+
+		attempts = append(attempts, models.StreamAttempt{
+			Server: server.Name,
+			Type:   server.Type,
+			Error:  err.Error(),
+		})
+	}
+
+	var failures []string
+	for _, attempt := range attempts {
+		failures = append(failures, fmt.Sprintf("%s (%s): %s", attempt.Server, attempt.Type, attempt.Error))
+	}
+
+	return nil, fmt.Errorf("all servers failed: %s", strings.Join(failures, "; "))
+}
+
+// orderServers sorts servers by the configured preference, keeping page order for unlisted servers
+func (s *Scraper) orderServers(servers []models.Server) []models.Server {
+	rank := func(name string) int {
+		for i, preferred := range s.config.ServerPreference {
+			if strings.EqualFold(preferred, name) {
+				return i
+			}
+		}
+		return len(s.config.ServerPreference)
+	}
+
+	ordered := make([]models.Server, len(servers))
+	copy(ordered, servers)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank(ordered[i].Name) < rank(ordered[j].Name)
+	})
+
+	return ordered
 }
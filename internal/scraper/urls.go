@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// buildURL joins path segments onto the configured base URL and appends the encoded
+// query. Each segment is escaped on its own, so a "/", "?" or "#" inside an ID or name
+// stays part of that segment instead of changing the request.
+func (s *Scraper) buildURL(query url.Values, segments ...string) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(s.config.BaseURL, "/"))
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+
+	if len(query) > 0 {
+		b.WriteByte('?')
+		b.WriteString(query.Encode())
+	}
+
+	return b.String()
+}
+
+// pageQuery returns the query selecting a page of a listing
+func pageQuery(page int) url.Values {
+	return url.Values{"page": {strconv.Itoa(page)}}
+}
+
+// watchURL returns the watch page URL of an anime, optionally at an episode
+func (s *Scraper) watchURL(animeID, episode string) string {
+	var query url.Values
+	if episode != "" {
+		query = url.Values{"ep": {episode}}
+	}
+	return s.buildURL(query, "watch", animeID)
+}
@@ -0,0 +1,192 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ayanrajpoot10/hianime-api/config"
+)
+
+func newTestScraper(baseURL string) *Scraper {
+	cfg := config.DefaultConfig()
+	cfg.BaseURL = baseURL
+	return New(cfg)
+}
+
+func TestBuildURL(t *testing.T) {
+	s := newTestScraper("https://hianime.to")
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		segments []string
+		want     string
+	}{
+		{
+			name:     "plain segments",
+			segments: []string{"ajax", "v2", "episode", "list", "100"},
+			want:     "https://hianime.to/ajax/v2/episode/list/100",
+		},
+		{
+			name:  "no segments",
+			query: url.Values{"page": {"2"}},
+			want:  "https://hianime.to?page=2",
+		},
+		{
+			name:     "japanese keyword",
+			query:    url.Values{"keyword": {"ワンピース"}},
+			segments: []string{"search"},
+			want:     "https://hianime.to/search?keyword=%E3%83%AF%E3%83%B3%E3%83%94%E3%83%BC%E3%82%B9",
+		},
+		{
+			name:     "keyword with spaces",
+			query:    url.Values{"keyword": {"one piece"}},
+			segments: []string{"search"},
+			want:     "https://hianime.to/search?keyword=one+piece",
+		},
+		{
+			name:     "keyword with ampersand, question mark and slash",
+			query:    url.Values{"keyword": {"fate/zero & more?"}},
+			segments: []string{"search"},
+			want:     "https://hianime.to/search?keyword=fate%2Fzero+%26+more%3F",
+		},
+		{
+			name:     "japanese path segment",
+			segments: []string{"genre", "日常"},
+			want:     "https://hianime.to/genre/%E6%97%A5%E5%B8%B8",
+		},
+		{
+			name:     "path segment with space",
+			segments: []string{"producer", "studio ghibli"},
+			want:     "https://hianime.to/producer/studio%20ghibli",
+		},
+		{
+			name:     "path segment with slash stays one segment",
+			segments: []string{"producer", "a/b"},
+			want:     "https://hianime.to/producer/a%2Fb",
+		},
+		{
+			name:     "path segment with question mark and hash",
+			segments: []string{"producer", "what?#now"},
+			want:     "https://hianime.to/producer/what%3F%23now",
+		},
+		{
+			name:     "path segment with ampersand",
+			segments: []string{"producer", "a&b"},
+			want:     "https://hianime.to/producer/a&b",
+		},
+		{
+			name:     "multiple query values are sorted",
+			query:    url.Values{"tzOffset": {"-330"}, "date": {"2024-01-15"}},
+			segments: []string{"ajax", "schedule", "list"},
+			want:     "https://hianime.to/ajax/schedule/list?date=2024-01-15&tzOffset=-330",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.buildURL(tt.query, tt.segments...); got != tt.want {
+				t.Errorf("buildURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildURLRoundTrip(t *testing.T) {
+	s := newTestScraper("https://hianime.to")
+
+	for _, keyword := range []string{"ワンピース", "進撃の巨人", "one piece", "a&b=c", "what?", "fate/zero", "100%"} {
+		t.Run(keyword, func(t *testing.T) {
+			u, err := url.Parse(s.buildURL(url.Values{"keyword": {keyword}}, "search"))
+			if err != nil {
+				t.Fatalf("failed to parse built URL: %v", err)
+			}
+			if u.Path != "/search" {
+				t.Errorf("path = %q, want %q", u.Path, "/search")
+			}
+			if got := u.Query().Get("keyword"); got != keyword {
+				t.Errorf("keyword = %q, want %q", got, keyword)
+			}
+		})
+	}
+}
+
+func TestBuildURLTrailingSlashBase(t *testing.T) {
+	s := newTestScraper("https://hianime.to/")
+
+	if got, want := s.buildURL(nil, "home"), "https://hianime.to/home"; got != want {
+		t.Errorf("buildURL() = %q, want %q", got, want)
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	tests := []struct {
+		page int
+		want string
+	}{
+		{1, "page=1"},
+		{12, "page=12"},
+	}
+
+	for _, tt := range tests {
+		if got := pageQuery(tt.page).Encode(); got != tt.want {
+			t.Errorf("pageQuery(%d) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestWatchURL(t *testing.T) {
+	s := newTestScraper("https://hianime.to")
+
+	tests := []struct {
+		animeID string
+		episode string
+		want    string
+	}{
+		{"one-piece-100", "", "https://hianime.to/watch/one-piece-100"},
+		{"one-piece-100", "2142", "https://hianime.to/watch/one-piece-100?ep=2142"},
+	}
+
+	for _, tt := range tests {
+		if got := s.watchURL(tt.animeID, tt.episode); got != tt.want {
+			t.Errorf("watchURL(%q, %q) = %q, want %q", tt.animeID, tt.episode, got, tt.want)
+		}
+	}
+}
+
+func TestSearchKeywordQuery(t *testing.T) {
+	const keyword = "ワンピース & more?"
+
+	requests := map[string]url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path] = r.URL.Query()
+		if r.URL.Path == "/ajax/search/suggest" {
+			w.Write([]byte(`{"status":true,"html":""}`))
+		}
+	}))
+	defer server.Close()
+
+	s := newTestScraper(server.URL)
+	if _, err := s.Search(keyword, 1); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if _, err := s.Suggestions(keyword); err != nil {
+		t.Fatalf("Suggestions() error = %v", err)
+	}
+
+	for _, path := range []string{"/search", "/ajax/search/suggest"} {
+		query, ok := requests[path]
+		if !ok {
+			t.Errorf("no request to %s", path)
+			continue
+		}
+		if got := query.Get("keyword"); got != keyword {
+			t.Errorf("%s keyword = %q, want %q", path, got, keyword)
+		}
+	}
+	if got := requests["/search"].Get("page"); got != "1" {
+		t.Errorf("/search page = %q, want %q", got, "1")
+	}
+}
@@ -0,0 +1,147 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayanrajpoot10/hianime-api/internal/hls"
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// expiryParams lists signed URL parameters holding an expiry unix timestamp
+var expiryParams = []string{"expires", "expire", "expiry", "exp", "e", "validto", "valid_to", "deadline"}
+
+// VerifyStream checks that a stream's playlist and first segment are reachable with
+// the stream's headers and records the outcome, latency and link expiry on the response
+func (s *Scraper) VerifyStream(stream *models.StreamResponse) error {
+	verified := false
+	stream.Verified = &verified
+	stream.VerifyError = ""
+
+	if expiresAt, ok := parseExpiry(stream.Link.File); ok {
+		stream.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+		stream.ExpiresIn = int64(time.Until(expiresAt).Seconds())
+	}
+
+	err := s.verifyPlaylist(stream)
+	if err != nil {
+		stream.VerifyError = err.Error()
+		return err
+	}
+
+	verified = true
+	return nil
+}
+
+// verifyPlaylist fetches the playlist chain down to the first media segment
+func (s *Scraper) verifyPlaylist(stream *models.StreamResponse) error {
+	if stream.Link.File == "" {
+		return fmt.Errorf("stream has no link")
+	}
+
+	start := time.Now()
+	body, base, err := s.fetchForVerify(stream.Link.File, stream.Headers, "")
+	if err != nil {
+		return fmt.Errorf("playlist unreachable: %w", err)
+	}
+	stream.LatencyMs = time.Since(start).Milliseconds()
+
+	if !strings.HasPrefix(strings.TrimSpace(string(body)), "#EXTM3U") {
+		return fmt.Errorf("playlist is empty or invalid")
+	}
+
+	if hls.IsMaster(body) {
+		variants, err := hls.ParseMaster(body, base)
+		if err != nil {
+			return err
+		}
+
+		body, base, err = s.fetchForVerify(variants[0].URI, stream.Headers, "")
+		if err != nil {
+			return fmt.Errorf("media playlist unreachable: %w", err)
+		}
+	}
+
+	media, err := hls.ParseMedia(body, base)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := s.fetchForVerify(media.Segments[0].URI, stream.Headers, "bytes=0-1023"); err != nil {
+		return fmt.Errorf("first segment unreachable: %w", err)
+	}
+
+	return nil
+}
+
+// fetchForVerify performs a single GET without retries so dead links fail fast
+func (s *Scraper) fetchForVerify(rawURL string, headers map[string]string, byteRange string) ([]byte, *url.URL, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4*1024*1024))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return body, resp.Request.URL, nil
+}
+
+// parseExpiry extracts the expiry time from common signed URL parameters
+func parseExpiry(rawURL string) (time.Time, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		for _, param := range expiryParams {
+			if !strings.EqualFold(key, param) || len(values) == 0 {
+				continue
+			}
+			if ts, err := strconv.ParseInt(values[0], 10, 64); err == nil && ts > 0 {
+				// Some hosts sign with millisecond timestamps
+				if ts > 1e12 {
+					return time.UnixMilli(ts), true
+				}
+				return time.Unix(ts, 0), true
+			}
+		}
+	}
+
+	// AWS style signatures carry a signing date and a lifetime in seconds
+	if date, lifetime := query.Get("X-Amz-Date"), query.Get("X-Amz-Expires"); date != "" && lifetime != "" {
+		signed, err := time.Parse("20060102T150405Z", date)
+		seconds, convErr := strconv.Atoi(lifetime)
+		if err == nil && convErr == nil {
+			return signed.Add(time.Duration(seconds) * time.Second), true
+		}
+	}
+
+	return time.Time{}, false
+}
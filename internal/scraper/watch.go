@@ -0,0 +1,88 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Watch scrapes everything the watch page shows for an episode: its servers of every
+// type, episode-level notices and the next-airing banner. The servers list and the
+// page itself are fetched concurrently.
+func (s *Scraper) Watch(episodeID string) (*models.WatchResponse, error) {
+	animeID, episode, err := parseEpisodeID(episodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		servers            *models.ServersResponse
+		doc                *goquery.Document
+		serversErr, docErr error
+		wg                 sync.WaitGroup
+	)
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		servers, serversErr = s.Servers(episodeID)
+	}()
+	go func() {
+		defer wg.Done()
+		doc, docErr = s.watchPage(animeID, episode)
+	}()
+	wg.Wait()
+
+	if serversErr != nil {
+		return nil, fmt.Errorf("failed to get servers: %w", serversErr)
+	}
+	if docErr != nil {
+		return nil, fmt.Errorf("failed to get watch page: %w", docErr)
+	}
+
+	response := &models.WatchResponse{
+		EpisodeID: episodeID,
+		Servers:   servers,
+		Notices:   []string{},
+	}
+
+	for _, notice := range append(servers.Notices, parseNotices(doc.Find(".watch-notice, .anime-notice, #anime-notice .alert"))...) {
+		if !contains(response.Notices, notice) {
+			response.Notices = append(response.Notices, notice)
+		}
+	}
+
+	if next := parseNextAiring(doc); next.Banner != "" || next.AiringTimestamp != nil {
+		response.NextEpisode = next
+	}
+
+	return response, nil
+}
+
+// watchPage fetches the watch page of an episode
+func (s *Scraper) watchPage(animeID, episode string) (*goquery.Document, error) {
+	url := s.watchURL(animeID, episode)
+
+	resp, err := s.client.GetWithHeaders(url, map[string]string{
+		"Referer": s.config.BaseURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,152 @@
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported output formats
+const (
+	FormatWebVTT = "vtt"
+	FormatSubRip = "srt"
+	FormatASS    = "ass"
+)
+
+// ContentTypes maps output formats to their MIME types
+var ContentTypes = map[string]string{
+	FormatWebVTT: "text/vtt; charset=utf-8",
+	FormatSubRip: "application/x-subrip; charset=utf-8",
+	FormatASS:    "text/x-ssa; charset=utf-8",
+}
+
+var (
+	// cueTagRegex matches any WebVTT cue markup tag
+	cueTagRegex = regexp.MustCompile(`</?[^>]+>`)
+	// styleTagRegex matches the tags SRT and ASS can represent
+	styleTagRegex = regexp.MustCompile(`</?([ibu])(?:\.[^>]*)?>`)
+	// timestampTagRegex matches inline karaoke timestamps like <00:01.000>
+	timestampTagRegex = regexp.MustCompile(`<\d[\d:.]*>`)
+)
+
+// Convert renders cues in the requested format
+func Convert(cues []Cue, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatWebVTT:
+		return FormatVTT(cues), nil
+	case FormatSubRip:
+		return FormatSRT(cues), nil
+	case FormatASS, "ssa":
+		return FormatASSDocument(cues), nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle format: %s", format)
+	}
+}
+
+// FormatSRT renders cues as a SubRip document
+func FormatSRT(cues []Cue) string {
+	var b strings.Builder
+
+	for i, cue := range cues {
+		b.WriteString(strconv.Itoa(i+1) + "\n")
+		b.WriteString(formatClock(cue.Start, ",") + " --> " + formatClock(cue.End, ",") + "\n")
+		b.WriteString(srtText(cue.Text) + "\n\n")
+	}
+
+	return b.String()
+}
+
+// FormatASSDocument renders cues as an Advanced SubStation Alpha document
+func FormatASSDocument(cues []Cue) string {
+	var b strings.Builder
+
+	b.WriteString("[Script Info]\n")
+	b.WriteString("ScriptType: v4.00+\n")
+	b.WriteString("PlayResX: 1920\n")
+	b.WriteString("PlayResY: 1080\n")
+	b.WriteString("WrapStyle: 0\n")
+	b.WriteString("ScaledBorderAndShadow: yes\n\n")
+
+	b.WriteString("[V4+ Styles]\n")
+	b.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	b.WriteString("Style: Default,Arial,64,&H00FFFFFF,&H000000FF,&H00000000,&H64000000,0,0,0,0,100,100,0,0,1,3,1,2,60,60,50,1\n\n")
+
+	b.WriteString("[Events]\n")
+	b.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+
+	for _, cue := range cues {
+		b.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n",
+			assClock(cue.Start), assClock(cue.End), assText(cue.Text)))
+	}
+
+	return b.String()
+}
+
+// srtText keeps the italic, bold and underline tags SubRip understands and drops the rest
+func srtText(text string) string {
+	text = timestampTagRegex.ReplaceAllString(text, "")
+	text = keepStyleTags(text, func(tag string, closing bool) string {
+		if closing {
+			return "</" + tag + ">"
+		}
+		return "<" + tag + ">"
+	})
+	return unescapeEntities(text)
+}
+
+// assText converts cue markup into ASS override tags
+func assText(text string) string {
+	text = timestampTagRegex.ReplaceAllString(text, "")
+	text = keepStyleTags(text, func(tag string, closing bool) string {
+		if closing {
+			return `{\` + tag + `0}`
+		}
+		return `{\` + tag + `1}`
+	})
+	text = unescapeEntities(text)
+	return strings.ReplaceAll(text, "\n", `\N`)
+}
+
+// keepStyleTags rewrites supported style tags with render and strips any other markup
+func keepStyleTags(text string, render func(tag string, closing bool) string) string {
+	const placeholder = "\x00"
+
+	var kept []string
+	text = styleTagRegex.ReplaceAllStringFunc(text, func(match string) string {
+		groups := styleTagRegex.FindStringSubmatch(match)
+		kept = append(kept, render(groups[1], strings.HasPrefix(match, "</")))
+		return placeholder
+	})
+
+	text = cueTagRegex.ReplaceAllString(text, "")
+
+	for _, tag := range kept {
+		text = strings.Replace(text, placeholder, tag, 1)
+	}
+
+	return text
+}
+
+// unescapeEntities decodes the character references allowed in cue text
+func unescapeEntities(text string) string {
+	replacer := strings.NewReplacer(
+		"&amp;", "&",
+		"&lt;", "<",
+		"&gt;", ">",
+		"&nbsp;", " ",
+		"&lrm;", "\u200e",
+		"&rlm;", "\u200f",
+	)
+	return replacer.Replace(text)
+}
+
+// assClock formats a duration as h:mm:ss.cc
+func assClock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, (cs/6000)%60, (cs/100)%60, cs%100)
+}
@@ -0,0 +1,80 @@
+package subtitle
+
+import (
+	"path"
+	"strings"
+)
+
+// languageCodes maps track labels to ISO 639-1 codes
+var languageCodes = map[string]string{
+	"arabic":     "ar",
+	"chinese":    "zh",
+	"czech":      "cs",
+	"dutch":      "nl",
+	"english":    "en",
+	"finnish":    "fi",
+	"french":     "fr",
+	"german":     "de",
+	"greek":      "el",
+	"hebrew":     "he",
+	"hindi":      "hi",
+	"hungarian":  "hu",
+	"indonesian": "id",
+	"italian":    "it",
+	"japanese":   "ja",
+	"korean":     "ko",
+	"malay":      "ms",
+	"persian":    "fa",
+	"polish":     "pl",
+	"portuguese": "pt",
+	"romanian":   "ro",
+	"russian":    "ru",
+	"spanish":    "es",
+	"swedish":    "sv",
+	"thai":       "th",
+	"turkish":    "tr",
+	"ukrainian":  "uk",
+	"vietnamese": "vi",
+}
+
+// fileLanguageCodes maps the three letter prefixes used in track file names
+var fileLanguageCodes = map[string]string{
+	"ara": "ar",
+	"chi": "zh",
+	"eng": "en",
+	"fre": "fr",
+	"ger": "de",
+	"ind": "id",
+	"ita": "it",
+	"jpn": "ja",
+	"kor": "ko",
+	"may": "ms",
+	"por": "pt",
+	"rus": "ru",
+	"spa": "es",
+	"tha": "th",
+	"tur": "tr",
+	"vie": "vi",
+}
+
+// LanguageCode guesses the ISO 639-1 code of a track from its label, falling
+// back to the language prefix of its file name (e.g. "eng-2.vtt")
+func LanguageCode(label, file string) string {
+	// Labels look like "English", "Portuguese - Portuguese(Brazil)" or "Spanish - Spanish(Latin_America)"
+	name := strings.ToLower(strings.TrimSpace(label))
+	if idx := strings.IndexAny(name, " -("); idx > 0 {
+		name = name[:idx]
+	}
+	if code, ok := languageCodes[name]; ok {
+		return code
+	}
+
+	base := strings.ToLower(path.Base(file))
+	if len(base) >= 3 {
+		if code, ok := fileLanguageCodes[base[:3]]; ok {
+			return code
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,54 @@
+package subtitle
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ayanrajpoot10/hianime-api/pkg/models"
+)
+
+// ParseThumbnails converts the cues of a thumbnails track into seek-preview
+// entries. Cue text is a sprite URL relative to the track, optionally with a
+// "#xywh=x,y,w,h" fragment selecting a region of the sprite sheet.
+func ParseThumbnails(cues []Cue, base *url.URL) []models.Thumbnail {
+	thumbnails := make([]models.Thumbnail, 0, len(cues))
+
+	for _, cue := range cues {
+		text := strings.TrimSpace(strings.Split(cue.Text, "\n")[0])
+		if text == "" {
+			continue
+		}
+
+		thumb := models.Thumbnail{
+			Start: cue.Start.Seconds(),
+			End:   cue.End.Seconds(),
+		}
+
+		sprite := text
+		if idx := strings.Index(text, "#"); idx >= 0 {
+			sprite = text[:idx]
+			fragment := text[idx+1:]
+			if strings.HasPrefix(fragment, "xywh=") {
+				coords := strings.Split(strings.TrimPrefix(fragment, "xywh="), ",")
+				if len(coords) == 4 {
+					thumb.X, _ = strconv.Atoi(strings.TrimSpace(coords[0]))
+					thumb.Y, _ = strconv.Atoi(strings.TrimSpace(coords[1]))
+					thumb.W, _ = strconv.Atoi(strings.TrimSpace(coords[2]))
+					thumb.H, _ = strconv.Atoi(strings.TrimSpace(coords[3]))
+				}
+			}
+		}
+
+		thumb.URL = sprite
+		if base != nil {
+			if ref, err := url.Parse(sprite); err == nil {
+				thumb.URL = base.ResolveReference(ref).String()
+			}
+		}
+
+		thumbnails = append(thumbnails, thumb)
+	}
+
+	return thumbnails
+}
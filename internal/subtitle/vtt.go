@@ -0,0 +1,196 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cue represents a single timed subtitle entry
+type Cue struct {
+	ID       string
+	Start    time.Duration
+	End      time.Duration
+	Settings string
+	Text     string
+}
+
+// ParseVTT parses a WebVTT document into cues. NOTE, STYLE and REGION
+// blocks are skipped.
+func ParseVTT(r io.Reader) ([]Cue, error) {
+	var cues []Cue
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var block []string
+	flush := func() error {
+		defer func() { block = block[:0] }()
+		if len(block) == 0 {
+			return nil
+		}
+
+		first := block[0]
+		if strings.HasPrefix(first, "WEBVTT") || strings.HasPrefix(first, "NOTE") ||
+			strings.HasPrefix(first, "STYLE") || strings.HasPrefix(first, "REGION") {
+			return nil
+		}
+
+		cue := Cue{}
+		timingIdx := 0
+		if !strings.Contains(first, "-->") {
+			if len(block) < 2 || !strings.Contains(block[1], "-->") {
+				return nil
+			}
+			cue.ID = strings.TrimSpace(first)
+			timingIdx = 1
+		}
+
+		start, end, settings, err := parseTiming(block[timingIdx])
+		if err != nil {
+			return err
+		}
+		cue.Start = start
+		cue.End = end
+		cue.Settings = settings
+		cue.Text = strings.Join(block[timingIdx+1:], "\n")
+
+		cues = append(cues, cue)
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\ufeff")
+
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read subtitle: %w", err)
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return cues, nil
+}
+
+// parseTiming parses a "start --> end [settings]" cue timing line
+func parseTiming(line string) (time.Duration, time.Duration, string, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return 0, 0, "", fmt.Errorf("invalid cue timing: %s", line)
+	}
+
+	start, err := ParseTimestamp(parts[0])
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	rest := strings.Fields(parts[1])
+	if len(rest) == 0 {
+		return 0, 0, "", fmt.Errorf("invalid cue timing: %s", line)
+	}
+
+	end, err := ParseTimestamp(rest[0])
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	return start, end, strings.Join(rest[1:], " "), nil
+}
+
+// ParseTimestamp parses a WebVTT timestamp in "hh:mm:ss.ttt" or "mm:ss.ttt" form
+func ParseTimestamp(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	value = strings.Replace(value, ",", ".", 1)
+
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+
+	var hours, minutes int
+	var err error
+	if len(parts) == 3 {
+		if hours, err = strconv.Atoi(parts[0]); err != nil {
+			return 0, fmt.Errorf("invalid timestamp: %s", value)
+		}
+		parts = parts[1:]
+	}
+
+	if minutes, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+
+	seconds, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp: %s", value)
+	}
+
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	total += time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+
+	return total, nil
+}
+
+// Shift moves every cue by offset, dropping cues that end before zero
+func Shift(cues []Cue, offset time.Duration) []Cue {
+	if offset == 0 {
+		return cues
+	}
+
+	shifted := make([]Cue, 0, len(cues))
+	for _, cue := range cues {
+		cue.Start += offset
+		cue.End += offset
+		if cue.End <= 0 {
+			continue
+		}
+		if cue.Start < 0 {
+			cue.Start = 0
+		}
+		shifted = append(shifted, cue)
+	}
+
+	return shifted
+}
+
+// FormatVTT renders cues as a WebVTT document
+func FormatVTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+
+	for _, cue := range cues {
+		if cue.ID != "" {
+			b.WriteString(cue.ID + "\n")
+		}
+		b.WriteString(formatClock(cue.Start, ".") + " --> " + formatClock(cue.End, "."))
+		if cue.Settings != "" {
+			b.WriteString(" " + cue.Settings)
+		}
+		b.WriteString("\n" + cue.Text + "\n\n")
+	}
+
+	return b.String()
+}
+
+// formatClock formats a duration as hh:mm:ss followed by sep and milliseconds
+func formatClock(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, sep, ms%1000)
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"slices"
+	"strings"
+)
+
 // AnimeItem represents a single anime item with all possible fields
 type AnimeItem struct {
 	ID          string      `json:"id"`
@@ -18,6 +23,7 @@ type AnimeItem struct {
 	Characters  []Character `json:"characters,omitempty"`
 	Genres      []string    `json:"genres,omitempty"`
 	URL         string      `json:"url,omitempty"`
+	Relation    string      `json:"relation,omitempty"`
 }
 
 // Episodes represents episode information
@@ -29,10 +35,90 @@ type Episodes struct {
 
 // Character represents anime character information
 type Character struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Picture string `json:"picture"`
-	Role    string `json:"role,omitempty"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Picture     string       `json:"picture"`
+	Role        string       `json:"role,omitempty"`
+	VoiceActors []VoiceActor `json:"voiceActors,omitempty"`
+}
+
+// VoiceActor represents a voice actor of a character
+type VoiceActor struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Picture  string `json:"picture"`
+	Language string `json:"language,omitempty"`
+}
+
+// CharactersResponse represents the full character and voice actor list of an anime
+type CharactersResponse struct {
+	AnimeID    string      `json:"animeId"`
+	Total      int         `json:"total"`
+	Characters []Character `json:"characters"`
+}
+
+// FilmographyEntry represents an anime appearance of a character or a voice role of a person
+type FilmographyEntry struct {
+	AnimeID          string `json:"animeId"`
+	AnimeTitle       string `json:"animeTitle"`
+	AnimePoster      string `json:"animePoster,omitempty"`
+	AnimeType        string `json:"animeType,omitempty"`
+	CharacterID      string `json:"characterId,omitempty"`
+	CharacterName    string `json:"characterName,omitempty"`
+	CharacterPicture string `json:"characterPicture,omitempty"`
+	Role             string `json:"role,omitempty"`
+}
+
+// CharacterDetailResponse represents a character page
+type CharacterDetailResponse struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	JName        string             `json:"jname,omitempty"`
+	Picture      string             `json:"picture"`
+	Bio          string             `json:"bio,omitempty"`
+	VoiceActors  []VoiceActor       `json:"voiceActors"`
+	Animeography []FilmographyEntry `json:"animeography"`
+}
+
+// PersonDetailResponse represents a voice actor or staff page
+type PersonDetailResponse struct {
+	ID       string             `json:"id"`
+	Name     string             `json:"name"`
+	JName    string             `json:"jname,omitempty"`
+	Picture  string             `json:"picture"`
+	Bio      string             `json:"bio,omitempty"`
+	Language string             `json:"language,omitempty"`
+	Roles    []FilmographyEntry `json:"roles"`
+}
+
+// Trailer represents a trailer or promotional video
+type Trailer struct {
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// FranchiseNode represents an anime in a franchise graph
+type FranchiseNode struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Poster string `json:"poster,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Depth  int    `json:"depth"`
+}
+
+// FranchiseEdge represents a season or relation link between two anime
+type FranchiseEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"`
+}
+
+// FranchiseResponse represents the seasons and related anime reachable from an anime
+type FranchiseResponse struct {
+	Root  string          `json:"root"`
+	Nodes []FranchiseNode `json:"nodes"`
+	Edges []FranchiseEdge `json:"edges"`
 }
 
 // Season represents a season of an anime
@@ -75,6 +161,10 @@ type AnimeDetailResponse struct {
 	Source            string      `json:"source,omitempty"`
 	PremiereDate      string      `json:"premiere_date,omitempty"`
 	Synonyms          []string    `json:"synonyms,omitempty"`
+	Japanese          string      `json:"japanese,omitempty"`
+	MALID             int         `json:"mal_id,omitempty"`
+	AniListID         int         `json:"anilist_id,omitempty"`
+	Trailers          []Trailer   `json:"trailers,omitempty"`
 	RelatedAnimes     []AnimeItem `json:"related_animes,omitempty"`
 	RecommendedAnimes []AnimeItem `json:"recommended_animes,omitempty"`
 	OtherSeasons      []Season    `json:"other_seasons,omitempty"`
@@ -95,12 +185,67 @@ type EpisodeInfo struct {
 	JName    string `json:"jname,omitempty"`
 	Episode  int    `json:"episode"`
 	IsFiller bool   `json:"is_filler"`
+	Type     string `json:"type,omitempty"`
+	Arc      string `json:"arc,omitempty"`
+
+	Availability *EpisodeAvailability `json:"availability,omitempty"`
+}
+
+// EpisodeAvailability represents the stream types and servers offered for an episode
+type EpisodeAvailability struct {
+	Types   []string            `json:"types"`
+	Servers map[string][]string `json:"servers"`
+	Error   string              `json:"error,omitempty"`
 }
 
 // EpisodesResponse represents episodes list response
 type EpisodesResponse struct {
 	Episodes   []EpisodeInfo `json:"episodes"`
 	TotalItems int           `json:"totalItems"`
+	Matched    int           `json:"matched,omitempty"`
+	Offset     int           `json:"offset,omitempty"`
+	Limit      int           `json:"limit,omitempty"`
+	HasMore    bool          `json:"hasMore,omitempty"`
+	Source     string        `json:"source,omitempty"`
+}
+
+// ArcGroup represents the episodes of one story arc
+type ArcGroup struct {
+	Name     string         `json:"name"`
+	Start    int            `json:"start"`
+	End      int            `json:"end"`
+	Type     string         `json:"type"`
+	Counts   map[string]int `json:"counts"`
+	Episodes []EpisodeInfo  `json:"episodes"`
+}
+
+// ArcsResponse represents episodes grouped by arc
+type ArcsResponse struct {
+	AnimeID    string     `json:"animeId"`
+	Source     string     `json:"source"`
+	TotalItems int        `json:"totalItems"`
+	Arcs       []ArcGroup `json:"arcs"`
+}
+
+// CatalogueEntry represents a genre, category or producer with its slug and page link
+type CatalogueEntry struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// CatalogueResponse represents a list of genres, categories or producers
+type CatalogueResponse struct {
+	Items []CatalogueEntry `json:"items"`
+	Total int              `json:"total"`
+}
+
+// WatchResponse represents the servers, notices and next-airing banner of a watch page
+type WatchResponse struct {
+	EpisodeID   string                       `json:"episodeId"`
+	Servers     *ServersResponse             `json:"servers"`
+	Notices     []string                     `json:"notices"`
+	NextEpisode *NextEpisodeScheduleResponse `json:"nextEpisode,omitempty"`
 }
 
 // Server represents a streaming server
@@ -113,21 +258,74 @@ type Server struct {
 
 // ServersResponse represents available servers for an episode
 type ServersResponse struct {
-	Episode int      `json:"episode"`
-	Sub     []Server `json:"sub"`
-	Dub     []Server `json:"dub"`
+	Episode int                 `json:"episode"`
+	Types   []string            `json:"types"`
+	Sub     []Server            `json:"sub"`
+	Dub     []Server            `json:"dub"`
+	Other   map[string][]Server `json:"other,omitempty"`
+	Notices []string            `json:"notices,omitempty"`
+}
+
+// List returns the servers of a type such as "sub", "dub" or "raw". An empty type means sub.
+func (r *ServersResponse) List(serverType string) []Server {
+	switch serverType = strings.ToLower(serverType); serverType {
+	case "", "sub":
+		return r.Sub
+	case "dub":
+		return r.Dub
+	default:
+		return r.Other[serverType]
+	}
+}
+
+// Add appends a server to the list of its type, recording new types in page order
+func (r *ServersResponse) Add(server Server) {
+	serverType := strings.ToLower(server.Type)
+	if !slices.Contains(r.Types, serverType) {
+		r.Types = append(r.Types, serverType)
+	}
+
+	switch serverType {
+	case "sub":
+		r.Sub = append(r.Sub, server)
+	case "dub":
+		r.Dub = append(r.Dub, server)
+	default:
+		if r.Other == nil {
+			r.Other = make(map[string][]Server)
+		}
+		r.Other[serverType] = append(r.Other[serverType], server)
+	}
 }
 
 // StreamResponse represents streaming links and sources (matches JS API)
 type StreamResponse struct {
-	ID     string     `json:"id"`
-	Type   string     `json:"type"`
-	Link   StreamLink `json:"link"`
-	Tracks []Track    `json:"tracks,omitempty"`
-	Intro  *TimeRange `json:"intro,omitempty"`
-	Outro  *TimeRange `json:"outro,omitempty"`
-	Server string     `json:"server"`
-	Iframe string     `json:"iframe,omitempty"`
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Link     StreamLink        `json:"link"`
+	Tracks   []Track           `json:"tracks,omitempty"`
+	Intro    *TimeRange        `json:"intro,omitempty"`
+	Outro    *TimeRange        `json:"outro,omitempty"`
+	Recap    *TimeRange        `json:"recap,omitempty"`
+	Credits  *TimeRange        `json:"credits,omitempty"`
+	Server   string            `json:"server"`
+	Fallback string            `json:"fallback,omitempty"`
+	Iframe   string            `json:"iframe,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Attempts []StreamAttempt   `json:"attempts,omitempty"`
+
+	Verified    *bool  `json:"verified,omitempty"`
+	LatencyMs   int64  `json:"latencyMs,omitempty"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	ExpiresIn   int64  `json:"expiresIn,omitempty"`
+	VerifyError string `json:"verifyError,omitempty"`
+}
+
+// StreamAttempt records a server tried during automatic server selection
+type StreamAttempt struct {
+	Server string `json:"server"`
+	Type   string `json:"type"`
+	Error  string `json:"error,omitempty"`
 }
 
 // StreamLink represents the main streaming link
@@ -138,8 +336,64 @@ type StreamLink struct {
 
 // Track represents subtitle/thumbnail tracks
 type Track struct {
-	File string `json:"file"`
-	Kind string `json:"kind"`
+	File     string `json:"file"`
+	Kind     string `json:"kind"`
+	Label    string `json:"label,omitempty"`
+	Language string `json:"language,omitempty"`
+	Default  bool   `json:"default,omitempty"`
+}
+
+// Thumbnail represents a seek-preview region inside a sprite sheet
+type Thumbnail struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	URL   string  `json:"url"`
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+	W     int     `json:"w"`
+	H     int     `json:"h"`
+}
+
+// ThumbnailsResponse represents parsed seek-preview thumbnails for a stream
+type ThumbnailsResponse struct {
+	Track      string      `json:"track"`
+	Sprites    []string    `json:"sprites"`
+	Thumbnails []Thumbnail `json:"thumbnails"`
+}
+
+// TokenCandidate represents a token found on an embed page by one extraction strategy
+type TokenCandidate struct {
+	Strategy string `json:"strategy"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// TokenDiagnostics lists every token candidate found on an embed page
+type TokenDiagnostics struct {
+	URL        string           `json:"url"`
+	Server     string           `json:"server,omitempty"`
+	Token      string           `json:"token"`
+	Strategy   string           `json:"strategy"`
+	Order      []string         `json:"order"`
+	Candidates []TokenCandidate `json:"candidates"`
+}
+
+// FallbackHostStats represents the success rate of a megacloud fallback mirror
+type FallbackHostStats struct {
+	Host        string  `json:"host"`
+	Enabled     bool    `json:"enabled"`
+	Attempts    int     `json:"attempts"`
+	Successes   int     `json:"successes"`
+	Failures    int     `json:"failures"`
+	SuccessRate float64 `json:"successRate"`
+	LastError   string  `json:"lastError,omitempty"`
+	LastSuccess string  `json:"lastSuccess,omitempty"`
+	LastFailure string  `json:"lastFailure,omitempty"`
+}
+
+// FallbackStatsResponse represents the success rates of all fallback mirrors
+type FallbackStatsResponse struct {
+	Hosts []FallbackHostStats `json:"hosts"`
 }
 
 // TimeRange represents intro/outro time ranges
@@ -148,6 +402,43 @@ type TimeRange struct {
 	End   int `json:"end"`
 }
 
+// IsEmpty reports whether the range is missing or has no duration
+func (tr *TimeRange) IsEmpty() bool {
+	return tr == nil || tr.End <= tr.Start
+}
+
+// SkipSource represents the skip markers reported by one server and source path
+type SkipSource struct {
+	Server  string     `json:"server"`
+	Type    string     `json:"type"`
+	Path    string     `json:"path"`
+	Intro   *TimeRange `json:"intro,omitempty"`
+	Outro   *TimeRange `json:"outro,omitempty"`
+	Recap   *TimeRange `json:"recap,omitempty"`
+	Credits *TimeRange `json:"credits,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// SkipRange represents a skip marker reconciled across servers
+type SkipRange struct {
+	Start      int      `json:"start"`
+	End        int      `json:"end"`
+	Votes      int      `json:"votes"`
+	Confidence float64  `json:"confidence"`
+	Servers    []string `json:"servers"`
+	Conflict   bool     `json:"conflict,omitempty"`
+}
+
+// SkipTimesResponse represents intro/outro/recap/credits markers aggregated across servers
+type SkipTimesResponse struct {
+	EpisodeID string       `json:"episodeId"`
+	Intro     *SkipRange   `json:"intro"`
+	Outro     *SkipRange   `json:"outro"`
+	Recap     *SkipRange   `json:"recap,omitempty"`
+	Credits   *SkipRange   `json:"credits,omitempty"`
+	Sources   []SkipSource `json:"sources"`
+}
+
 // StreamSource represents a video source (legacy)
 type StreamSource struct {
 	URL     string `json:"url"`
@@ -189,12 +480,22 @@ type QtipResponse struct {
 	Anime QtipAnime `json:"anime"`
 }
 
+// ResolveResponse represents an anime ID, link or episode ID resolved to canonical IDs
+type ResolveResponse struct {
+	Input     string `json:"input"`
+	AnimeID   string `json:"animeId"`
+	NumericID string `json:"numericId"`
+	EpisodeID string `json:"episodeId,omitempty"`
+	URL       string `json:"url"`
+}
+
 // ScheduledAnime represents a scheduled anime episode
 type ScheduledAnime struct {
 	ID                 string `json:"id"`
 	Time               string `json:"time,omitempty"`
 	Name               string `json:"name,omitempty"`
 	JName              string `json:"jname,omitempty"`
+	AiringISOTimestamp string `json:"airingISOTimestamp,omitempty"`
 	AiringTimestamp    int64  `json:"airingTimestamp"`
 	SecondsUntilAiring int64  `json:"secondsUntilAiring"`
 	Episode            int    `json:"episode"`
@@ -202,11 +503,31 @@ type ScheduledAnime struct {
 
 // EstimatedScheduleResponse represents the response structure for estimated schedule data
 type EstimatedScheduleResponse struct {
+	Date            string           `json:"date,omitempty"`
+	Timezone        string           `json:"timezone,omitempty"`
 	ScheduledAnimes []ScheduledAnime `json:"scheduledAnimes"`
 }
 
+// ScheduleDay represents the scheduled animes of one day of a weekly schedule
+type ScheduleDay struct {
+	Date            string           `json:"date"`
+	Weekday         string           `json:"weekday"`
+	ScheduledAnimes []ScheduledAnime `json:"scheduledAnimes"`
+	Error           string           `json:"error,omitempty"`
+}
+
+// WeeklyScheduleResponse represents the estimated schedule of a week grouped by day
+type WeeklyScheduleResponse struct {
+	Timezone   string        `json:"timezone"`
+	StartDate  string        `json:"startDate"`
+	EndDate    string        `json:"endDate"`
+	TotalItems int           `json:"totalItems"`
+	Days       []ScheduleDay `json:"days"`
+}
+
 // NextEpisodeScheduleResponse represents the response structure for next episode schedule data
 type NextEpisodeScheduleResponse struct {
+	Banner             string `json:"banner,omitempty"`
 	AiringISOTimestamp string `json:"airingISOTimestamp,omitempty"`
 	AiringTimestamp    *int64 `json:"airingTimestamp,omitempty"`
 	SecondsUntilAiring *int64 `json:"secondsUntilAiring,omitempty"`
@@ -266,34 +587,15 @@ type AZListResponse struct {
 	CurrentPage int         `json:"currentPage"`
 }
 
-// ProducerAnime represents an anime from a producer page
-type ProducerAnime struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	Poster   string    `json:"poster"`
-	Duration string    `json:"duration"`
-	Type     string    `json:"type"`
-	Rating   string    `json:"rating"`
-	Episodes *Episodes `json:"episodes,omitempty"`
-}
-
 // ProducerResponse represents the response from the producer endpoint
 type ProducerResponse struct {
-	ProducerName          string           `json:"producerName"`
-	Animes                []ProducerAnime  `json:"animes"`
-	Top10Animes           Top10            `json:"top10Animes"`
-	TopAiringAnimes       []TopAiringAnime `json:"topAiringAnimes"`
-	TotalPages            int              `json:"totalPages"`
-	CurrentPage           int              `json:"currentPage"`
-	HasNextPage           bool             `json:"hasNextPage"`
-}
-
-// TopAiringAnime represents a top airing anime item
-type TopAiringAnime struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	Poster   string    `json:"poster"`
-	Episodes *Episodes `json:"episodes,omitempty"`
+	ProducerName    string      `json:"producerName"`
+	Animes          []AnimeItem `json:"animes"`
+	Top10Animes     Top10       `json:"top10Animes"`
+	TopAiringAnimes []AnimeItem `json:"topAiringAnimes"`
+	TotalPages      int         `json:"totalPages"`
+	CurrentPage     int         `json:"currentPage"`
+	HasNextPage     bool        `json:"hasNextPage"`
 }
 
 // APIResponse represents a generic API response wrapper